@@ -0,0 +1,20 @@
+package main
+
+import "time"
+
+// Clock tells the time behind the timestamps the exercises show.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Latency turns the delay an exercise simulates into the time it actually waits.
+// The server waits as long as each exercise says; tests skip the waiting.
+type Latency func(time.Duration) time.Duration
+
+func fullLatency(d time.Duration) time.Duration { return d }
+
+func noLatency(time.Duration) time.Duration { return 0 }
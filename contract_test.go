@@ -0,0 +1,696 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+// ----------------------------------------------------------------------------------
+// HTMX contract: every selector handed to htmx has to find an element
+// ----------------------------------------------------------------------------------
+// The index points buttons at IDs like #ex1-target that the server fragments must
+// keep. The checker loads the page, follows each hx-get, hx-post, hx-put and
+// hx-delete, swaps the response in the way htmx would and then resolves every
+// hx-target, hx-target-*, hx-include and hx-indicator against the result. Requests
+// that change state send the values htmx would send, each from a new visitor, so
+// the order they run in does not matter.
+
+// contractFetch answers a request the way the server does for htmx
+type contractFetch func(method, path string, form url.Values) (status int, header http.Header, body []byte, err error)
+
+// The request attributes the checker follows, with their methods
+var requestAttrs = []struct{ key, method string }{
+	{"hx-get", http.MethodGet},
+	{"hx-post", http.MethodPost},
+	{"hx-put", http.MethodPut},
+	{"hx-patch", http.MethodPatch},
+	{"hx-delete", http.MethodDelete},
+}
+
+type contractChecker struct {
+	fetch    contractFetch
+	maxDepth int // how many swaps deep to follow requests
+	followed map[string]bool
+	reported map[string]bool
+	problems []string
+}
+
+func newContractChecker(fetch contractFetch) *contractChecker {
+	return &contractChecker{fetch: fetch, maxDepth: 3, followed: map[string]bool{}, reported: map[string]bool{}}
+}
+
+func (c *contractChecker) report(format string, args ...interface{}) {
+	problem := fmt.Sprintf(format, args...)
+	if !c.reported[problem] {
+		c.reported[problem] = true
+		c.problems = append(c.problems, problem)
+	}
+}
+
+// checkPage checks the page as loaded and after every swap reachable from it.
+func (c *contractChecker) checkPage(doc *html.Node, where string) {
+	c.checkSelectors(doc, where)
+	c.followRequests(doc, elements(doc), where, 1)
+}
+
+// checkSelectors resolves every selector attribute in doc.
+func (c *contractChecker) checkSelectors(doc *html.Node, where string) {
+	for _, elt := range elements(doc) {
+		for _, a := range elt.Attr {
+			if !isSelectorAttr(a.Key) {
+				continue
+			}
+			found, err := query(doc, elt, a.Val)
+			if err != nil {
+				c.report("%s: %s %s: %v", where, describe(elt), a.Key, err)
+			} else if len(found) == 0 {
+				c.report("%s: %s %s=%q matches nothing", where, describe(elt), a.Key, a.Val)
+			}
+		}
+	}
+}
+
+func isSelectorAttr(name string) bool {
+	return name == "hx-target" || name == "hx-include" || name == "hx-indicator" || strings.HasPrefix(name, "hx-target-")
+}
+
+// followRequests swaps in the response of each request in scope, on a copy of doc.
+func (c *contractChecker) followRequests(doc *html.Node, scope []*html.Node, where string, depth int) {
+	for _, original := range scope {
+		// hx-select keeps part of a full page, which this checker does not model
+		if closestWith(original, "hx-select") != nil {
+			continue
+		}
+		for _, ra := range requestAttrs {
+			if path := attr(original, ra.key); path != "" {
+				c.follow(doc, original, ra.method, path, depth)
+			}
+		}
+	}
+}
+
+// follow sends the request of original and checks the page after the swap.
+func (c *contractChecker) follow(doc, original *html.Node, method, path string, depth int) {
+	page, nodes := cloneTree(doc)
+	elt := nodes[original]
+	target, err := requestTarget(page, elt)
+	if err != nil || target == nil {
+		return // already reported by checkSelectors
+	}
+	label := fmt.Sprintf("%s %s into %s", method, path, describe(target))
+	if c.followed[label] {
+		return
+	}
+	c.followed[label] = true
+
+	var form url.Values
+	if method != http.MethodGet {
+		form = formValues(page, elt)
+	}
+	status, header, body, err := c.fetch(method, path, form)
+	if err != nil {
+		c.report("%s: %v", label, err)
+		return
+	}
+	// htmx leaves the page alone for errors and 204 No Content
+	if status != http.StatusOK {
+		return
+	}
+	if retarget := header.Get("HX-Retarget"); retarget != "" {
+		found, err := query(page, elt, retarget)
+		if err != nil || len(found) == 0 {
+			c.report("%s: HX-Retarget %q matches nothing", label, retarget)
+			return
+		}
+		target = found[0]
+	}
+	style := "innerHTML"
+	if swap := closestWith(elt, "hx-swap"); swap != nil {
+		style = strings.Fields(attr(swap, "hx-swap") + " innerHTML")[0]
+	}
+	if reswap := header.Get("HX-Reswap"); reswap != "" {
+		style = strings.Fields(reswap)[0]
+	}
+
+	inserted, err := c.swap(page, target, style, body, label)
+	if err != nil {
+		c.report("%s: %v", label, err)
+		return
+	}
+	c.checkSelectors(page, label)
+	if depth < c.maxDepth {
+		var next []*html.Node
+		for _, n := range inserted {
+			next = append(next, elements(n)...)
+		}
+		c.followRequests(page, next, label, depth+1)
+	}
+}
+
+// formValues collects what htmx sends with a request that is not a GET: the
+// enclosing form, elt itself, whatever hx-include names and the static hx-vals.
+// Empty fields are filled in, so that the request gets past validation. "js:"
+// hx-vals only exist in the browser and are left out.
+func formValues(doc, elt *html.Node) url.Values {
+	sources := []*html.Node{elt}
+	if form := closestTag(elt, "form"); form != nil {
+		sources = append(sources, form)
+	}
+	if decl := closestWith(elt, "hx-include"); decl != nil {
+		found, _ := query(doc, elt, attr(decl, "hx-include"))
+		sources = append(sources, found...)
+	}
+
+	form := url.Values{}
+	seen := map[*html.Node]bool{}
+	for _, source := range sources {
+		for _, field := range elements(source) {
+			name := attr(field, "name")
+			if name == "" || seen[field] || hasAttr(field, "disabled") {
+				continue
+			}
+			seen[field] = true
+			switch field.Data {
+			case "input":
+				switch kind := attr(field, "type"); kind {
+				case "checkbox", "radio":
+					if hasAttr(field, "checked") {
+						form.Add(name, cmp.Or(attr(field, "value"), "on"))
+					}
+				case "submit", "button", "reset", "image", "file":
+				default:
+					form.Add(name, cmp.Or(attr(field, "value"), sampleValue(kind, field)))
+				}
+			case "textarea":
+				form.Add(name, cmp.Or(textContent(field), sampleValue("text", field)))
+			case "select":
+				form.Add(name, selectedOption(field))
+			case "button":
+				if field == elt {
+					form.Add(name, attr(field, "value"))
+				}
+			}
+		}
+	}
+
+	if decl := closestWith(elt, "hx-vals"); decl != nil && !strings.HasPrefix(attr(decl, "hx-vals"), "js:") {
+		var vals map[string]any
+		if err := json.Unmarshal([]byte(attr(decl, "hx-vals")), &vals); err == nil {
+			for k, v := range vals {
+				form.Set(k, fmt.Sprint(v))
+			}
+		}
+	}
+	return form
+}
+
+// sampleValue is what a visitor might type into an empty field of the given type.
+func sampleValue(kind string, field *html.Node) string {
+	switch kind {
+	case "email":
+		return "ann@example.com"
+	case "password":
+		return "Correct-Horse-7"
+	case "number", "range":
+		return cmp.Or(attr(field, "min"), "1")
+	case "date":
+		return "2024-03-01"
+	}
+	return "Ann"
+}
+
+func selectedOption(sel *html.Node) string {
+	var first *html.Node
+	for _, opt := range elements(sel) {
+		if opt.Data != "option" {
+			continue
+		}
+		if first == nil {
+			first = opt
+		}
+		if hasAttr(opt, "selected") {
+			first = opt
+			break
+		}
+	}
+	if first == nil {
+		return ""
+	}
+	if hasAttr(first, "value") {
+		return attr(first, "value")
+	}
+	return strings.TrimSpace(textContent(first))
+}
+
+// requestTarget finds where the response of elt goes: the closest hx-target, where
+// "this" means the element that declares it, or elt itself.
+func requestTarget(doc, elt *html.Node) (*html.Node, error) {
+	decl := closestWith(elt, "hx-target")
+	if decl == nil {
+		return elt, nil
+	}
+	sel := attr(decl, "hx-target")
+	if sel == "this" {
+		return decl, nil
+	}
+	found, err := query(doc, elt, sel)
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return found[0], nil
+}
+
+// swap parses body in the context of target and puts it in place, handling
+// hx-swap-oob elements first. It returns the nodes that were inserted.
+func (c *contractChecker) swap(doc, target *html.Node, style string, body []byte, label string) ([]*html.Node, error) {
+	parent := target
+	if style == "outerHTML" || style == "beforebegin" || style == "afterend" {
+		parent = target.Parent
+	}
+	if parent == nil || parent.Type != html.ElementNode {
+		return nil, fmt.Errorf("cannot swap %s around %s", style, describe(target))
+	}
+	fragment, err := html.ParseFragment(bytes.NewReader(body), &html.Node{Type: html.ElementNode, Data: parent.Data, DataAtom: parent.DataAtom})
+	if err != nil {
+		return nil, err
+	}
+
+	var inserted, main []*html.Node
+	for _, n := range fragment {
+		if n.Type != html.ElementNode || attr(n, "hx-swap-oob") == "" {
+			main = append(main, n)
+			continue
+		}
+		id := attr(n, "id")
+		existing := byID(doc, id)
+		if existing == nil {
+			c.report("%s: hx-swap-oob element #%s has no counterpart on the page", label, id)
+			continue
+		}
+		removeAttr(n, "hx-swap-oob")
+		existing.Parent.InsertBefore(n, existing)
+		existing.Parent.RemoveChild(existing)
+		inserted = append(inserted, n)
+	}
+
+	switch style {
+	case "innerHTML":
+		for target.FirstChild != nil {
+			target.RemoveChild(target.FirstChild)
+		}
+		appendAll(target, nil, main)
+	case "outerHTML":
+		appendAll(target.Parent, target, main)
+		target.Parent.RemoveChild(target)
+	case "beforebegin":
+		appendAll(target.Parent, target, main)
+	case "afterend":
+		appendAll(target.Parent, target.NextSibling, main)
+	case "afterbegin":
+		appendAll(target, target.FirstChild, main)
+	case "beforeend":
+		appendAll(target, nil, main)
+	case "delete":
+		target.Parent.RemoveChild(target)
+		return inserted, nil
+	case "none":
+		return inserted, nil
+	default:
+		return nil, fmt.Errorf("unknown swap style %q", style)
+	}
+	return append(inserted, main...), nil
+}
+
+func appendAll(parent, before *html.Node, nodes []*html.Node) {
+	for _, n := range nodes {
+		parent.InsertBefore(n, before)
+	}
+}
+
+// ----------------------------------------------------------------------------------
+// Selectors
+// ----------------------------------------------------------------------------------
+
+// Compound selectors such as button, #ex1-target, .error or div.card.alert
+var (
+	compoundSelector = regexp.MustCompile(`^([a-zA-Z][\w-]*|\*)?((?:[#.][\w-]+)*)$`)
+	qualifier        = regexp.MustCompile(`[#.][\w-]+`)
+)
+
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// selector is a comma-separated list of compound selectors
+type selector []compound
+
+func parseSelector(sel string) (selector, error) {
+	var parsed selector
+	for _, part := range strings.Split(sel, ",") {
+		m := compoundSelector.FindStringSubmatch(strings.TrimSpace(part))
+		if m == nil || m[0] == "" {
+			return nil, fmt.Errorf("unsupported selector %q", sel)
+		}
+		c := compound{tag: m[1]}
+		for _, q := range qualifier.FindAllString(m[2], -1) {
+			if q[0] == '#' {
+				c.id = q[1:]
+			} else {
+				c.classes = append(c.classes, q[1:])
+			}
+		}
+		parsed = append(parsed, c)
+	}
+	return parsed, nil
+}
+
+func (s selector) matches(n *html.Node) bool {
+	for _, c := range s {
+		if c.matches(n) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c compound) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode || c.tag != "" && c.tag != "*" && !strings.EqualFold(c.tag, n.Data) {
+		return false
+	}
+	if c.id != "" && attr(n, "id") != c.id {
+		return false
+	}
+	classes := strings.Fields(attr(n, "class"))
+	for _, want := range c.classes {
+		if !slices.Contains(classes, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s selector) filter(nodes []*html.Node) []*html.Node {
+	var found []*html.Node
+	for _, n := range nodes {
+		if s.matches(n) {
+			found = append(found, n)
+		}
+	}
+	return found
+}
+
+// query resolves an htmx extended selector relative to elt, like htmx does for
+// hx-target and friends. Plain CSS is limited to comma-separated compound selectors.
+func query(doc, elt *html.Node, sel string) ([]*html.Node, error) {
+	sel = strings.TrimSpace(sel)
+	switch sel {
+	case "this":
+		return []*html.Node{elt}, nil
+	case "document", "window", "body":
+		return []*html.Node{doc}, nil
+	case "next":
+		for n := elt.NextSibling; n != nil; n = n.NextSibling {
+			if n.Type == html.ElementNode {
+				return []*html.Node{n}, nil
+			}
+		}
+		return nil, nil
+	case "previous":
+		for n := elt.PrevSibling; n != nil; n = n.PrevSibling {
+			if n.Type == html.ElementNode {
+				return []*html.Node{n}, nil
+			}
+		}
+		return nil, nil
+	}
+
+	keyword, rest, _ := strings.Cut(sel, " ")
+	switch keyword {
+	case "closest", "find", "next", "previous":
+		sel = rest
+	default:
+		keyword = ""
+	}
+	parsed, err := parseSelector(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	switch keyword {
+	case "closest":
+		for n := elt; n != nil; n = n.Parent {
+			if parsed.matches(n) {
+				return []*html.Node{n}, nil
+			}
+		}
+		return nil, nil
+	case "find":
+		return parsed.filter(elements(elt)[1:]), nil
+	case "next", "previous":
+		all := elements(doc)
+		pos := slices.Index(all, elt)
+		if keyword == "next" {
+			return parsed.filter(all[pos+1:]), nil
+		}
+		return parsed.filter(all[:max(pos, 0)]), nil
+	}
+	return parsed.filter(elements(doc)), nil
+}
+
+// ----------------------------------------------------------------------------------
+// Tree helpers
+// ----------------------------------------------------------------------------------
+
+// elements lists n and every element below it in document order.
+func elements(n *html.Node) []*html.Node {
+	var all []*html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			all = append(all, n)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return all
+}
+
+// cloneTree deep-copies n and maps each original node to its copy.
+func cloneTree(n *html.Node) (*html.Node, map[*html.Node]*html.Node) {
+	nodes := map[*html.Node]*html.Node{}
+	var clone func(*html.Node) *html.Node
+	clone = func(n *html.Node) *html.Node {
+		c := &html.Node{Type: n.Type, DataAtom: n.DataAtom, Data: n.Data, Namespace: n.Namespace, Attr: append([]html.Attribute(nil), n.Attr...)}
+		nodes[n] = c
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			c.AppendChild(clone(child))
+		}
+		return c
+	}
+	return clone(n), nodes
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func removeAttr(n *html.Node, key string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return
+		}
+	}
+}
+
+// closestWith finds n or the nearest ancestor that sets the attribute.
+func closestWith(n *html.Node, key string) *html.Node {
+	for ; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode && attr(n, key) != "" {
+			return n
+		}
+	}
+	return nil
+}
+
+// closestTag finds n or the nearest ancestor with the given tag.
+func closestTag(n *html.Node, tag string) *html.Node {
+	for ; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode && n.Data == tag {
+			return n
+		}
+	}
+	return nil
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func byID(doc *html.Node, id string) *html.Node {
+	for _, n := range elements(doc) {
+		if attr(n, "id") == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// describe names an element for error messages, e.g. <button hx-get="/exercise1/reset">.
+func describe(n *html.Node) string {
+	if id := attr(n, "id"); id != "" {
+		return fmt.Sprintf("<%s id=%q>", n.Data, id)
+	}
+	for _, key := range []string{"hx-get", "hx-post", "hx-put", "hx-delete", "name", "class"} {
+		if v := attr(n, key); v != "" {
+			return fmt.Sprintf("<%s %s=%q>", n.Data, key, v)
+		}
+	}
+	return "<" + n.Data + ">"
+}
+
+// ----------------------------------------------------------------------------------
+// Tests
+// ----------------------------------------------------------------------------------
+
+// serverFetch issues htmx-style requests against srv without following redirects.
+// There is no cookie jar, so every request comes from a new visitor.
+func serverFetch(t *testing.T) contractFetch {
+	srv := newTestServer(t)
+	client := srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return func(method, path string, form url.Values) (int, http.Header, []byte, error) {
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(form.Encode()))
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		req.Header.Set("HX-Request", "true")
+		if form != nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return resp.StatusCode, resp.Header, body, err
+	}
+}
+
+func fetchDocument(t *testing.T, fetch contractFetch, path string) *html.Node {
+	t.Helper()
+	status, _, body, err := fetch(http.MethodGet, path, nil)
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("GET %s: status %d, %v", path, status, err)
+	}
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	return doc
+}
+
+func TestHTMXContract(t *testing.T) {
+	// The like counters of exercise 14 are shared by every visitor
+	likes := make([]int64, len(ex14Posts))
+	for i, p := range ex14Posts {
+		likes[i] = p.Likes.Load()
+	}
+	t.Cleanup(func() {
+		for i, p := range ex14Posts {
+			p.Likes.Store(likes[i])
+		}
+	})
+
+	fetch := serverFetch(t)
+	c := newContractChecker(fetch)
+
+	c.checkPage(fetchDocument(t, fetch, "/"), "index")
+	// The code listings are standalone pages talking to the deployed server, so
+	// only their own markup is checked
+	for n := 1; n <= 21; n++ {
+		path := fmt.Sprintf("/code/exercise%d", n)
+		c.checkSelectors(fetchDocument(t, fetch, path), path)
+	}
+
+	for _, problem := range c.problems {
+		t.Error(problem)
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete} {
+		followed := false
+		for label := range c.followed {
+			followed = followed || strings.HasPrefix(label, method+" ")
+		}
+		if !followed {
+			t.Errorf("no %s was followed", method)
+		}
+	}
+}
+
+func TestHTMXContractCatchesDroppedID(t *testing.T) {
+	page := `<div id="demo"><button id="ex-target" hx-get="/reset" hx-target="#ex-target" hx-swap="outerHTML">Reset</button></div>`
+	tests := []struct {
+		name     string
+		fragment string
+		problems int
+	}{
+		{"keeps the id", `<button id="ex-target" hx-get="/reset" hx-target="#ex-target" hx-swap="outerHTML">Reset</button>`, 0},
+		{"drops the id", `<button hx-get="/reset" hx-target="#ex-target" hx-swap="outerHTML">Reset</button>`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newContractChecker(func(string, string, url.Values) (int, http.Header, []byte, error) {
+				return http.StatusOK, http.Header{}, []byte(tt.fragment), nil
+			})
+			doc, err := html.Parse(strings.NewReader(page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.checkPage(doc, "page")
+			if len(c.problems) != tt.problems {
+				t.Errorf("problems = %q, want %d", c.problems, tt.problems)
+			}
+		})
+	}
+}
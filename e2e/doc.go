@@ -0,0 +1,12 @@
+// Package e2e drives the tutorial page in a headless browser with chromedp.
+//
+// TestMain builds the server from the repository root and starts it on a free
+// port. Every request the page makes outside that server is answered from
+// testdata: the CDN scripts and styles from the copies checked in under
+// testdata/vendor, and the shiki highlighter from a small stand-in. Anything else
+// fails, so the scenarios never touch the network.
+//
+// The browser is $CHROME_PATH if set, otherwise the first Chrome or Chromium on
+// the PATH. Without a browser the tests are skipped; a missing vendored file
+// fails them.
+package e2e
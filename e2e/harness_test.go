@@ -0,0 +1,273 @@
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// How long one scenario may take, including the exercises' simulated latency
+const scenarioTimeout = 30 * time.Second
+
+// Files in testdata that answer the page's requests to CDNs
+var assetFiles = map[string]string{
+	"https://unpkg.com/htmx.org@1.9.12":                                       "vendor/htmx.min.js",
+	"https://unpkg.com/htmx.org@1.9.12/dist/ext/response-targets.js":          "vendor/response-targets.js",
+	"https://unpkg.com/htmx.org@1.9.12/dist/ext/preload.js":                   "vendor/preload.js",
+	"https://cdn.jsdelivr.net/npm/sortablejs@1.15.2/Sortable.min.js":          "vendor/Sortable.min.js",
+	"https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css": "vendor/bootstrap.min.css",
+	"https://esm.sh/shiki@1.0.0":                                              "shiki.js",
+}
+
+type asset struct {
+	body        []byte
+	contentType string
+}
+
+var (
+	serverURL string
+	assets    = map[string]asset{}
+	// Set when a vendored asset cannot be read; every test fails with it
+	assetErr error
+	// Set when there is no browser to run the scenarios in; every test skips with it
+	skipReason string
+	// The shared browser; each scenario opens its own browser context in it
+	browserCtx context.Context
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "htmx-e2e")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	stop, err := startServer(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "starting the server:", err)
+		return 1
+	}
+	defer stop()
+
+	if err := loadAssets(); err != nil {
+		assetErr = err
+		return m.Run()
+	}
+
+	cancel, err := startBrowser()
+	if err != nil {
+		skipReason = err.Error()
+		return m.Run()
+	}
+	defer cancel()
+	return m.Run()
+}
+
+// startServer builds the tutorial from the repository root and runs it on a free
+// port, with the templates found relative to the root like in production.
+func startServer(dir string) (stop func(), err error) {
+	bin := filepath.Join(dir, "server")
+	build := exec.Command("go", "build", "-o", bin, ".")
+	build.Dir = ".."
+	if out, err := build.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("go build: %v\n%s", err, out)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	port := fmt.Sprint(l.Addr().(*net.TCPAddr).Port)
+	l.Close()
+
+	var logs bytes.Buffer
+	cmd := exec.Command(bin)
+	cmd.Dir = ".."
+	cmd.Env = append(os.Environ(), "PORT="+port, "APP_ENV=")
+	cmd.Stdout = &logs
+	cmd.Stderr = &logs
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	stop = func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}
+
+	serverURL = "http://127.0.0.1:" + port
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if resp, err := http.Get(serverURL + "/"); err == nil {
+			resp.Body.Close()
+			return stop, nil
+		}
+	}
+	stop()
+	return nil, fmt.Errorf("server did not come up on %s\n%s", serverURL, logs.String())
+}
+
+func loadAssets() error {
+	for url, file := range assetFiles {
+		body, err := os.ReadFile(filepath.Join("testdata", file))
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("testdata/%s is missing; see testdata/vendor/README.md", file)
+		}
+		if err != nil {
+			return err
+		}
+		contentType := "text/javascript"
+		if strings.HasSuffix(file, ".css") {
+			contentType = "text/css"
+		}
+		assets[url] = asset{body, contentType}
+	}
+	return nil
+}
+
+func findBrowser() (string, error) {
+	if path := os.Getenv("CHROME_PATH"); path != "" {
+		return path, nil
+	}
+	for _, name := range []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser", "chrome", "headless-shell"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("no Chrome or Chromium found; set CHROME_PATH")
+}
+
+func startBrowser() (cancel func(), err error) {
+	path, err := findBrowser()
+	if err != nil {
+		return nil, err
+	}
+	opts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.ExecPath(path), chromedp.NoSandbox)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, cancelBrowser := chromedp.NewContext(allocCtx)
+	cancel = func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("%s did not start: %v", path, err)
+	}
+	browserCtx = ctx
+	return cancel, nil
+}
+
+// openIndex opens the index page in a new browser context, so cookies and with
+// them the server's per-session state start empty, and waits for htmx to load.
+func openIndex(t *testing.T) context.Context {
+	t.Helper()
+	if assetErr != nil {
+		t.Fatal(assetErr)
+	}
+	if skipReason != "" {
+		t.Skip(skipReason)
+	}
+	ctx, cancel := chromedp.NewContext(browserCtx, chromedp.WithNewBrowserContext())
+	t.Cleanup(cancel)
+	ctx, cancelTimeout := context.WithTimeout(ctx, scenarioTimeout)
+	t.Cleanup(cancelTimeout)
+
+	chromedp.ListenTarget(ctx, func(ev any) {
+		if ev, ok := ev.(*fetch.EventRequestPaused); ok {
+			go answer(ctx, ev)
+		}
+	})
+	err := chromedp.Run(ctx,
+		fetch.Enable().WithPatterns([]*fetch.RequestPattern{{URLPattern: "*"}}),
+		chromedp.Navigate(serverURL+"/"),
+		chromedp.Poll(`window.htmx !== undefined && window.translations !== undefined`, nil),
+	)
+	if err != nil {
+		t.Fatalf("loading the index: %v", err)
+	}
+	return ctx
+}
+
+// answer lets requests to the server through, serves the CDN assets from
+// testdata and fails everything else.
+func answer(ctx context.Context, ev *fetch.EventRequestPaused) {
+	ctx = cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+	url := ev.Request.URL
+	if strings.HasPrefix(url, serverURL+"/") {
+		fetch.ContinueRequest(ev.RequestID).Do(ctx)
+		return
+	}
+	a, ok := assets[url]
+	if !ok {
+		fetch.FailRequest(ev.RequestID, network.ErrorReasonInternetDisconnected).Do(ctx)
+		return
+	}
+	fetch.FulfillRequest(ev.RequestID, http.StatusOK).
+		WithResponseHeaders([]*fetch.HeaderEntry{
+			{Name: "Content-Type", Value: a.contentType},
+			{Name: "Access-Control-Allow-Origin", Value: "*"}, // the shiki stand-in is an ES module
+		}).
+		WithBody(base64.StdEncoding.EncodeToString(a.body)).
+		Do(ctx)
+}
+
+// ----------------------------------------------------------------------------------
+// Actions shared by the scenarios
+// ----------------------------------------------------------------------------------
+
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func click(sel string) chromedp.Action {
+	return chromedp.Click(sel, chromedp.ByQuery)
+}
+
+func typeInto(sel, text string) chromedp.Action {
+	return chromedp.SendKeys(sel, text, chromedp.ByQuery)
+}
+
+func setValue(sel, value string) chromedp.Action {
+	return chromedp.SetValue(sel, value, chromedp.ByQuery)
+}
+
+// waitFor polls a JavaScript condition, e.g. one that checks a swap happened.
+func waitFor(condition string) chromedp.Action {
+	return chromedp.Poll(condition, nil, chromedp.WithPollingTimeout(10*time.Second))
+}
+
+// waitText waits until the element matching sel contains text.
+func waitText(sel, text string) chromedp.Action {
+	return waitFor(fmt.Sprintf(`(document.querySelector(%s)?.textContent ?? "").includes(%s)`, jsString(sel), jsString(text)))
+}
+
+// waitElement waits until an element matches sel.
+func waitElement(sel string) chromedp.Action {
+	return waitFor(fmt.Sprintf(`document.querySelector(%s) !== null`, jsString(sel)))
+}
+
+// pause leaves time for a request that should not happen.
+func pause(d time.Duration) chromedp.Action {
+	return chromedp.Sleep(d)
+}
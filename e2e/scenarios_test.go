@@ -0,0 +1,158 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// One scenario per exercise: act on the live demo like a visitor would, then wait
+// for the swap that should follow
+var scenarios = []struct {
+	name  string
+	steps chromedp.Tasks
+}{
+	{"exercise1 click to change text", chromedp.Tasks{
+		click("#ex1-target"),
+		waitText("#ex1-target", "Clicked! ✅"),
+		click(`[hx-get="/exercise1/reset"]`),
+		waitText("#ex1-target", "Click Me"),
+	}},
+	{"exercise2 click to load content", chromedp.Tasks{
+		click(`[hx-get="/exercise2"]`),
+		waitText("#ex2-target", "Hello, HTMX!"),
+		click(`[hx-get="/exercise2/reset"]`),
+		waitFor(`document.querySelector("#ex2-target").textContent === ""`),
+	}},
+	{"exercise3 polling", chromedp.Tasks{
+		waitText("#ex3-target", "Server time is:"),
+	}},
+	{"exercise4 active search", chromedp.Tasks{
+		typeInto("#ex4-input", "gopher"),
+		waitText("#ex4-output", "gopher"),
+	}},
+	{"exercise5 form submission", chromedp.Tasks{
+		setValue("#name-ex5", "Jane"),
+		click(`#ex5-response button[type="submit"]`),
+		waitText("#ex5-response-wrapper", "Thank you, Jane!"),
+		click(`[hx-get="/exercise5/reset"]`),
+		waitElement("#name-ex5"),
+	}},
+	{"exercise6 click to edit", chromedp.Tasks{
+		click(`#contact-1 [hx-get="/exercise6/contact/1"]`),
+		waitElement(`#contact-1 input[name="name"]`),
+		setValue(`#contact-1 input[name="name"]`, "Ann Gopher"),
+		setValue(`#contact-1 input[name="email"]`, "ann@example.com"),
+		click(`#contact-1 button[type="submit"]`),
+		waitText("#contact-1", "Ann Gopher"),
+	}},
+	{"exercise7 server-triggered events", chromedp.Tasks{
+		typeInto(`#ex7-form input[name="name"]`, "Milk"),
+		click(`#ex7-form button[type="submit"]`),
+		waitText("#ex7-list", "Milk"),
+		waitText("#ex7-count", "1"),
+	}},
+	{"exercise8 another tab reorders", chromedp.Tasks{
+		click(`[hx-post="/exercise8/shuffle"]`),
+		waitText("#ex8-note", "Another tab moved"),
+	}},
+	{"exercise9 lazy widgets", chromedp.Tasks{
+		waitText("#ex9-demo", "24°C and sunny"),
+		chromedp.ScrollIntoView(`[hx-get="/exercise9/widget?card=news"]`, chromedp.ByQuery),
+		waitText("#ex9-demo", "HTMX keeps things simple"),
+	}},
+	{"exercise10 inline validation", chromedp.Tasks{
+		typeInto("#ex10-email", "not-an-email"),
+		waitText("#ex10-email + .error", "doesn't look like an email"),
+	}},
+	{"exercise11 trigger modifiers", chromedp.Tasks{
+		click(`[hx-get="/exercise11/hit?variant=once"]`),
+		waitText("#ex11-once-out", "1 request"),
+		click(`[hx-get="/exercise11/hit?variant=once"]`),
+		pause(500 * time.Millisecond),
+		waitText("#ex11-once-out", "1 request"),
+	}},
+	{"exercise12 hx-sync", chromedp.Tasks{
+		typeInto("#ex12-sync", "go"),
+		waitText("#ex12-sync-out", `for "go"`),
+	}},
+	{"exercise13 response targets", chromedp.Tasks{
+		click(`[hx-get="/exercise13/error?code=404"]`),
+		waitText("#ex13-errors", "There is no order #999."),
+		click(`[hx-get="/exercise13/error?code=422"]`),
+		waitText("#ex13-validation", "is not a valid email address"),
+		click(`[hx-get="/exercise13/error?code=200"]`),
+		waitText("#ex13-result", "Everything worked."),
+	}},
+	{"exercise14 optimistic like", chromedp.Tasks{
+		click("#ex14-post-1 button"),
+		waitElement("#ex14-post-1 button.btn-danger"),
+	}},
+	{"exercise15 add a card", chromedp.Tasks{
+		typeInto(`#ex15-demo form input[name="title"]`, "Write e2e tests\n"),
+		waitText("#ex15-col-todo", "Write e2e tests"),
+		waitText("#ex15-count-todo", "3"),
+	}},
+	{"exercise16 hx-include and hx-params", chromedp.Tasks{
+		click("#ex16-include"),
+		waitText("#ex16-echo", "included on request"),
+		click("#ex16-filter"),
+		waitFor(`(t => t.includes("Jane") && !t.includes("hunter2"))(document.querySelector("#ex16-echo").textContent)`),
+	}},
+	{"exercise17 hx-select and blocks", chromedp.Tasks{
+		click(`[hx-get="/exercise17/article?page=2"]`),
+		waitText("#ex17-block-out", "One template, many views"),
+		click(`[hx-get="/exercise17/article?page=3&full=1"]`),
+		waitText("#ex17-select-out", "Let the client choose"),
+		waitFor(`!document.querySelector("#ex17-stats").textContent.includes("Stats arrive")`),
+	}},
+	{"exercise18 wizard pushes the step URL", chromedp.Tasks{
+		setValue("#ex18-name", "Ann Gopher"),
+		setValue("#ex18-email", "ann@example.com"),
+		click(`#ex18-wizard button[type="submit"]`),
+		waitElement("#ex18-street"),
+		waitFor(`location.href.endsWith("/exercise18/step?n=2")`),
+	}},
+	{"exercise19 filter replaces the URL", chromedp.Tasks{
+		typeInto("#ex19-name", "Go"),
+		waitText("#ex19-table", "Robert Griesemer"),
+		waitFor(`location.search.includes("name=Go")`),
+	}},
+	{"exercise19 column toggles", chromedp.Tasks{
+		click(`#ex19-columns [hx-get="/exercise19/table?hide=year"]`),
+		waitFor(`location.search.includes("hide=year")`),
+		waitFor(`!document.querySelector("#ex19-table thead").textContent.includes("Year")`),
+	}},
+	{"exercise20 click without preload", chromedp.Tasks{
+		click(`[hx-get="/exercise20/item?id=0&mode=none"]`),
+		waitText("#ex20-view", "Rendered at"),
+		waitText("#ex20-stats", "Clicks that waited for the server: 1"),
+	}},
+	{"exercise21 duplicate submissions", chromedp.Tasks{
+		waitElement("#ex21-forms form[hx-sync]"),
+		click(`#ex21-forms form[hx-sync] button[type="submit"]`),
+		click(`#ex21-forms form[hx-sync] button[type="submit"]`),
+		waitText("#ex21-result", "Accepted from"),
+		click("#ex21-result button"),
+		waitText("#ex21-counts", "Deduplicated: 1"),
+		waitText("#ex21-counts", "Accepted: 1"),
+	}},
+	{"request inspector", chromedp.Tasks{
+		click("#ex1-target"),
+		waitText("#ex1-target", "Clicked! ✅"),
+		click(`details:has([hx-get="/inspector?exercise=1"]) summary`),
+		waitText(`[hx-get="/inspector?exercise=1"]`, "POST /exercise1"),
+	}},
+}
+
+func TestScenarios(t *testing.T) {
+	for _, sc := range scenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			ctx := openIndex(t)
+			if err := chromedp.Run(ctx, sc.steps); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"net/mail"
+	"strings"
+	"unicode"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 10: Inline Validation
+// ----------------------------------------------------------------------------------
+// Every field is checked on the server while the user types, using the same
+// debounced trigger as exercise 4. The final submit runs the same checks again,
+// because the per-field requests can always be skipped.
+
+// Emails that already "have an account"
+var ex10TakenEmails = map[string]bool{
+	"jane.doe@example.com": true,
+	"admin@example.com":    true,
+	"gopher@golang.org":    true,
+}
+
+type ex10Field struct {
+	Value string
+	Error string
+	Hint  string // shown when the value is valid
+}
+
+type ex10View struct {
+	Email       ex10Field
+	Password    ex10Field
+	SignupURL   string
+	EmailURL    string
+	PasswordURL string
+}
+
+func ex10CheckEmail(email string) ex10Field {
+	f := ex10Field{Value: email}
+	switch addr, err := mail.ParseAddress(email); {
+	case email == "":
+		f.Error = "Email is required."
+	case err != nil || addr.Address != email:
+		f.Error = "That doesn't look like an email address."
+	case ex10TakenEmails[strings.ToLower(email)]:
+		f.Error = "An account with this email already exists."
+	default:
+		f.Hint = "✓ Email is available."
+	}
+	return f
+}
+
+// ex10PasswordScore counts how many of length, lower case, upper case,
+// digits and symbols the password has (0-5).
+func ex10PasswordScore(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	score := 0
+	for _, ok := range []bool{len(password) >= 10, lower, upper, digit, symbol} {
+		if ok {
+			score++
+		}
+	}
+	return score
+}
+
+func ex10CheckPassword(password string) ex10Field {
+	// Never echo the password back into the page
+	f := ex10Field{}
+	switch score := ex10PasswordScore(password); {
+	case password == "":
+		f.Error = "Password is required."
+	case len(password) < 8:
+		f.Error = "Use at least 8 characters."
+	case score < 3:
+		f.Error = "Too weak: mix upper and lower case, digits and symbols."
+	case score < 5:
+		f.Hint = "✓ Fair password."
+	default:
+		f.Hint = "✓ Strong password."
+	}
+	return f
+}
+
+func addExercise10Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	newView := func() ex10View {
+		return ex10View{
+			SignupURL:   endpoint("/exercise10/signup"),
+			EmailURL:    endpoint("/exercise10/validate/email"),
+			PasswordURL: endpoint("/exercise10/validate/password"),
+		}
+	}
+
+	// Per-field checks: each returns only the message element under its input
+	mux.HandleFunc("POST /exercise10/validate/email", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex10Tmpl.ExecuteTemplate(w, "error", ex10CheckEmail(strings.TrimSpace(r.PostFormValue("email"))))
+	}))
+	mux.HandleFunc("POST /exercise10/validate/password", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex10Tmpl.ExecuteTemplate(w, "error", ex10CheckPassword(r.PostFormValue("password")))
+	}))
+
+	mux.HandleFunc("POST /exercise10/signup", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		view := newView()
+		view.Email = ex10CheckEmail(strings.TrimSpace(r.PostFormValue("email")))
+		view.Password = ex10CheckPassword(r.PostFormValue("password"))
+
+		if view.Email.Error != "" || view.Password.Error != "" {
+			ex10Tmpl.ExecuteTemplate(w, "form", view)
+			return
+		}
+		ex10Tmpl.ExecuteTemplate(w, "success", view)
+	}))
+
+	mux.HandleFunc("GET /exercise10/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex10Tmpl.ExecuteTemplate(w, "form", newView())
+	}))
+}
+
+var ex10Tmpl = template.Must(template.New("exercise10").Parse(`
+{{define "error"}}<div class="error small {{if .Error}}text-danger{{else}}text-success{{end}}">{{if .Error}}{{.Error}}{{else}}{{.Hint}}{{end}}</div>{{end}}
+
+{{define "form"}}<form id="ex10-form" hx-post="{{.SignupURL}}" hx-swap="outerHTML">
+    <div class="mb-2">
+        <label for="ex10-email" class="form-label small">Email</label>
+        <input type="email" id="ex10-email" name="email" class="form-control form-control-sm" value="{{.Email.Value}}"
+               hx-post="{{.EmailURL}}" hx-trigger="keyup changed delay:500ms" hx-target="next .error" hx-swap="outerHTML">
+        {{template "error" .Email}}
+    </div>
+    <div class="mb-3">
+        <label for="ex10-password" class="form-label small">Password</label>
+        <input type="password" id="ex10-password" name="password" class="form-control form-control-sm"
+               hx-post="{{.PasswordURL}}" hx-trigger="keyup changed delay:500ms" hx-target="next .error" hx-swap="outerHTML">
+        {{template "error" .Password}}
+    </div>
+    <button type="submit" class="btn btn-success btn-sm">Sign Up</button>
+</form>{{end}}
+
+{{define "success"}}<div id="ex10-form" class="alert alert-success mb-0">Welcome aboard, {{.Email.Value}}! Your account was created.</div>{{end}}
+`))
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 11: Trigger Filters & Modifiers
+// ----------------------------------------------------------------------------------
+// Every variant hits the same endpoint, which counts the requests it received.
+// Comparing the count with the number of clicks/keys shows what each modifier does.
+
+type ex11Variant struct {
+	Key     string
+	Label   string
+	Trigger string
+	Element string // "button", "input", or "listener" for a key that works anywhere
+	Slow    bool   // answer after a delay, so later events arrive while a request is in flight
+}
+
+var ex11Variants = []ex11Variant{
+	{Key: "enter", Label: "Type and press Enter", Trigger: "keyup[key=='Enter']", Element: "input"},
+	{Key: "hotkey", Label: "Press Escape anywhere on the page", Trigger: "keyup[key=='Escape'] from:body", Element: "listener"},
+	{Key: "once", Label: "Click me many times", Trigger: "click once", Element: "button"},
+	{Key: "throttle", Label: "Click me fast", Trigger: "click throttle:2s", Element: "button"},
+	{Key: "queue-last", Label: "Click me fast (slow server)", Trigger: "click queue:last", Element: "button", Slow: true},
+	{Key: "queue-all", Label: "Click me fast (slow server)", Trigger: "click queue:all", Element: "button", Slow: true},
+}
+
+type ex11Hit struct {
+	Count int
+	At    string
+}
+
+type ex11Row struct {
+	ex11Variant
+	URL string
+}
+
+// Requests received per variant
+type ex11Counts struct {
+	Hits map[string]int
+}
+
+var ex11Store = newSessionStore(func() *ex11Counts { return &ex11Counts{Hits: map[string]int{}} })
+
+func addExercise11Endpoints(mux *http.ServeMux, endpoint func(string) string, clock Clock, latency Latency) {
+	mux.HandleFunc("GET /exercise11/hit", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("variant")
+		var variant *ex11Variant
+		for i := range ex11Variants {
+			if ex11Variants[i].Key == key {
+				variant = &ex11Variants[i]
+				break
+			}
+		}
+		if variant == nil {
+			http.Error(w, "unknown variant", http.StatusNotFound)
+			return
+		}
+
+		// Count when the request arrives, not when the answer leaves
+		hit := ex11Hit{At: clock.Now().Format("15:04:05.000")}
+		ex11Store.with(w, r, func(c *ex11Counts) {
+			c.Hits[key]++
+			hit.Count = c.Hits[key]
+		})
+		if variant.Slow {
+			time.Sleep(latency(1 * time.Second))
+		}
+		ex11Tmpl.ExecuteTemplate(w, "hit", hit)
+	}))
+
+	mux.HandleFunc("GET /exercise11/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex11Store.reset(w, r)
+		var rows []ex11Row
+		for _, v := range ex11Variants {
+			rows = append(rows, ex11Row{ex11Variant: v, URL: endpoint("/exercise11/hit?variant=" + v.Key)})
+		}
+		ex11Tmpl.ExecuteTemplate(w, "demo", rows)
+	}))
+}
+
+var ex11Tmpl = template.Must(template.New("exercise11").Parse(`
+{{define "hit"}}<strong>{{.Count}}</strong> request{{if ne .Count 1}}s{{end}} <span class="text-muted">(last at {{.At}})</span>{{end}}
+
+{{define "demo"}}<table class="table table-sm align-middle small mb-0">
+    <thead><tr><th>hx-trigger</th><th>Try it</th><th>Server saw</th></tr></thead>
+    <tbody>
+        {{range .}}<tr>
+            <td><code>{{.Trigger}}</code></td>
+            <td>{{if eq .Element "input"}}<input type="text" class="form-control form-control-sm" placeholder="{{.Label}}" hx-get="{{.URL}}" hx-trigger="{{.Trigger}}" hx-target="#ex11-{{.Key}}-out">
+                {{- else if eq .Element "listener"}}<span class="text-muted" hx-get="{{.URL}}" hx-trigger="{{.Trigger}}" hx-target="#ex11-{{.Key}}-out">{{.Label}}</span>
+                {{- else}}<button class="btn btn-sm btn-outline-primary" hx-get="{{.URL}}" hx-trigger="{{.Trigger}}" hx-target="#ex11-{{.Key}}-out">{{.Label}}</button>{{end}}</td>
+            <td id="ex11-{{.Key}}-out">0 requests</td>
+        </tr>{{end}}
+    </tbody>
+</table>{{end}}
+`))
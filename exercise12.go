@@ -0,0 +1,98 @@
+package main
+
+import (
+	"html/template"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 12: Request Synchronization
+// ----------------------------------------------------------------------------------
+// The search endpoint answers after a random delay, so responses come back out of
+// order. Each response carries its sequence number and says whether a newer request
+// was already sent, which makes stale results overwriting fresh ones easy to spot.
+
+var ex12Words = []string{
+	"channel", "closure", "context", "defer", "embed", "generics", "goroutine",
+	"interface", "iota", "map", "method", "mutex", "package", "panic", "pointer",
+	"range", "recover", "select", "slice", "struct", "template", "waitgroup",
+}
+
+// Last sequence number handed out per search box
+type ex12State struct {
+	Seq map[string]int
+}
+
+var ex12Store = newSessionStore(func() *ex12State { return &ex12State{Seq: map[string]int{}} })
+
+type ex12Result struct {
+	Query   string
+	Seq     int
+	Latest  int
+	Took    time.Duration
+	Matches []string
+}
+
+func (r ex12Result) Stale() bool { return r.Seq < r.Latest }
+
+func ex12Search(q string) []string {
+	var matches []string
+	for _, word := range ex12Words {
+		if strings.Contains(word, strings.ToLower(q)) {
+			matches = append(matches, word)
+		}
+	}
+	return matches
+}
+
+func addExercise12Endpoints(mux *http.ServeMux, endpoint func(string) string, latency Latency) {
+	mux.HandleFunc("GET /exercise12/search", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		box := r.URL.Query().Get("box")
+		result := ex12Result{Query: strings.TrimSpace(r.URL.Query().Get("q"))}
+		ex12Store.with(w, r, func(s *ex12State) {
+			s.Seq[box]++
+			result.Seq = s.Seq[box]
+		})
+
+		// Anywhere between 0.2s and 2s, so a later request can easily finish first
+		result.Took = 200*time.Millisecond + rand.N(1800*time.Millisecond)
+		time.Sleep(latency(result.Took))
+
+		ex12Store.with(w, r, func(s *ex12State) { result.Latest = s.Seq[box] })
+		result.Took = result.Took.Round(time.Millisecond)
+		result.Matches = ex12Search(result.Query)
+		ex12Tmpl.ExecuteTemplate(w, "result", result)
+	}))
+
+	mux.HandleFunc("GET /exercise12/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex12Store.reset(w, r)
+		ex12Tmpl.ExecuteTemplate(w, "demo", map[string]string{
+			"PlainURL": endpoint("/exercise12/search?box=plain"),
+			"SyncURL":  endpoint("/exercise12/search?box=sync"),
+		})
+	}))
+}
+
+var ex12Tmpl = template.Must(template.New("exercise12").Parse(`
+{{define "result"}}<div class="alert {{if .Stale}}alert-warning{{else}}alert-success{{end}} py-1 px-2 small mb-0">
+    Request <strong>#{{.Seq}}</strong> for "{{.Query}}" took {{.Took}}.
+    {{if .Stale}}⚠️ Stale: request #{{.Latest}} was already sent.{{else}}✓ Latest request.{{end}}
+    <div class="text-muted">{{range $i, $m := .Matches}}{{if $i}}, {{end}}{{$m}}{{else}}No matches{{end}}</div>
+</div>{{end}}
+
+{{define "demo"}}<label class="form-label small mb-1">Without <code>hx-sync</code></label>
+<input type="search" name="q" class="form-control form-control-sm mb-2" placeholder="Type quickly, e.g. gor..."
+       hx-get="{{.PlainURL}}" hx-trigger="keyup changed" hx-target="#ex12-plain-out">
+<div id="ex12-plain-out" class="mb-3"></div>
+
+<label class="form-label small mb-1">With <code>hx-sync="this:replace"</code></label>
+<div class="input-group input-group-sm mb-2">
+    <input type="search" id="ex12-sync" name="q" class="form-control" placeholder="Type quickly, e.g. gor..."
+           hx-get="{{.SyncURL}}" hx-trigger="keyup changed" hx-sync="this:replace" hx-target="#ex12-sync-out">
+    <button type="button" class="btn btn-outline-danger" onclick="htmx.trigger('#ex12-sync', 'htmx:abort')">Abort</button>
+</div>
+<div id="ex12-sync-out"></div>{{end}}
+`))
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 13: Error Handling
+// ----------------------------------------------------------------------------------
+// Each button asks for a different failure. Error responses carry a fragment from
+// writeErrorFragment, and the response-targets extension decides where it goes.
+
+type ex13Scenario struct {
+	Code    string
+	Label   string
+	Status  int
+	Message string
+}
+
+var ex13Scenarios = []ex13Scenario{
+	{Code: "200", Label: "200 OK", Status: http.StatusOK, Message: "Everything worked."},
+	{Code: "400", Label: "400", Status: http.StatusBadRequest, Message: "The \"quantity\" parameter must be a number."},
+	{Code: "404", Label: "404", Status: http.StatusNotFound, Message: "There is no order #999."},
+	{Code: "422", Label: "422", Status: http.StatusUnprocessableEntity, Message: "\"not-an-email\" is not a valid email address."},
+	{Code: "500", Label: "500", Status: http.StatusInternalServerError, Message: "Something broke on our side. It has been logged."},
+	{Code: "timeout", Label: "Timeout"},
+}
+
+// How long the "timeout" scenario takes; longer than the page's 2s request timeout
+const ex13SlowResponse = 5 * time.Second
+
+func addExercise13Endpoints(mux *http.ServeMux, endpoint func(string) string, latency Latency) {
+	mux.HandleFunc("GET /exercise13/error", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		code := r.URL.Query().Get("code")
+		var scenario *ex13Scenario
+		for i := range ex13Scenarios {
+			if ex13Scenarios[i].Code == code {
+				scenario = &ex13Scenarios[i]
+				break
+			}
+		}
+
+		switch {
+		case scenario == nil:
+			writeErrorFragment(w, http.StatusNotFound, fmt.Sprintf("Unknown scenario %q.", code))
+		case scenario.Code == "timeout":
+			// Stop waiting as soon as the browser gives up
+			select {
+			case <-time.After(latency(ex13SlowResponse)):
+				fmt.Fprint(w, `<div class="alert alert-success py-2 small mb-0">Finally done.</div>`)
+			case <-r.Context().Done():
+				log.Println("Exercise 13: client gave up waiting")
+			}
+		case scenario.Status >= 500:
+			log.Printf("Exercise 13: simulated server error (%d)", scenario.Status)
+			writeErrorFragment(w, scenario.Status, scenario.Message)
+		case scenario.Status >= 400:
+			writeErrorFragment(w, scenario.Status, scenario.Message)
+		default:
+			fmt.Fprintf(w, `<div class="alert alert-success py-2 small mb-0"><strong>%d %s</strong><div>%s</div></div>`,
+				scenario.Status, http.StatusText(scenario.Status), template.HTMLEscapeString(scenario.Message))
+		}
+	}))
+
+	mux.HandleFunc("GET /exercise13/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		type button struct {
+			ex13Scenario
+			URL string
+		}
+		var buttons []button
+		for _, s := range ex13Scenarios {
+			buttons = append(buttons, button{s, endpoint("/exercise13/error?code=" + s.Code)})
+		}
+		ex13Tmpl.Execute(w, buttons)
+	}))
+}
+
+var ex13Tmpl = template.Must(template.New("exercise13").Parse(`<div hx-ext="response-targets" hx-target="#ex13-result" hx-target-4*="#ex13-errors" hx-target-5*="#ex13-errors" hx-request='{"timeout": 2000}'>
+    <div class="d-flex flex-wrap gap-1 mb-3">
+        {{range .}}<button class="btn btn-sm {{if eq .Code "200"}}btn-outline-success{{else}}btn-outline-danger{{end}}" hx-get="{{.URL}}"
+            {{- if eq .Code "422"}} hx-target-422="#ex13-validation"{{end}}>{{.Label}}</button>{{end}}
+    </div>
+    <div class="small text-muted">Success (hx-target)</div>
+    <div id="ex13-result" class="mb-2"></div>
+    <div class="small text-muted">Validation (hx-target-422)</div>
+    <div id="ex13-validation" class="mb-2"></div>
+    <div class="small text-muted">Errors (hx-target-4*, hx-target-5*)</div>
+    <div id="ex13-errors" class="mb-2"></div>
+    <pre id="ex13-log" class="small bg-light border rounded p-2 mb-0"></pre>
+</div>`))
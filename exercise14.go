@@ -0,0 +1,150 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 14: Optimistic UI
+// ----------------------------------------------------------------------------------
+// Like counts are shared by every visitor, so many requests can change them at once.
+// atomic.Int64 keeps each counter safe without a lock. Which posts *you* liked, and
+// the likes of the simulated crowd, are per-session state. The page bumps the count
+// before the server answers and then takes whatever number the server sends back.
+
+type ex14Post struct {
+	ID    int
+	Title string
+	Likes atomic.Int64
+}
+
+var ex14Posts = []*ex14Post{
+	{ID: 1, Title: "Why HTMX pairs well with Go"},
+	{ID: 2, Title: "Goroutines explained with cats"},
+	{ID: 3, Title: "Ten templates you will rewrite"},
+}
+
+type ex14State struct {
+	Liked map[int]bool
+	Crowd int64 // likes on every post from "other visitors", seen only by this visitor
+}
+
+var ex14Store = newSessionStore(func() *ex14State { return &ex14State{Liked: map[int]bool{}} })
+
+// Delay on writes, long enough to see the optimistic update come first
+const ex14WriteLatency = 600 * time.Millisecond
+
+type ex14View struct {
+	ID      int
+	Title   string
+	Likes   int64
+	Liked   bool
+	LikeURL string
+}
+
+func ex14FindPost(r *http.Request) *ex14Post {
+	id, _ := strconv.Atoi(r.URL.Query().Get("post"))
+	for _, p := range ex14Posts {
+		if p.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func addExercise14Endpoints(mux *http.ServeMux, endpoint func(string) string, latency Latency) {
+	newView := func(p *ex14Post, s *ex14State) ex14View {
+		return ex14View{
+			ID:      p.ID,
+			Title:   p.Title,
+			Likes:   p.Likes.Load() + s.Crowd,
+			Liked:   s.Liked[p.ID],
+			LikeURL: endpoint("/exercise14/like?post=" + strconv.Itoa(p.ID)),
+		}
+	}
+
+	// Renders the current count, polled by every tab
+	mux.HandleFunc("GET /exercise14/like", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		post := ex14FindPost(r)
+		if post == nil {
+			http.Error(w, "unknown post", http.StatusNotFound)
+			return
+		}
+		var view ex14View
+		ex14Store.with(w, r, func(s *ex14State) { view = newView(post, s) })
+		ex14Tmpl.ExecuteTemplate(w, "post", view)
+	}))
+	// Likes or unlikes the post
+	mux.HandleFunc("POST /exercise14/like", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		post := ex14FindPost(r)
+		if post == nil {
+			http.Error(w, "unknown post", http.StatusNotFound)
+			return
+		}
+		time.Sleep(latency(ex14WriteLatency))
+		// The button says what it wants, so a retried request can't count twice
+		want := r.PostFormValue("action") == "like"
+		var view ex14View
+		ex14Store.with(w, r, func(s *ex14State) {
+			if s.Liked[post.ID] != want {
+				s.Liked[post.ID] = want
+				if want {
+					post.Likes.Add(1)
+				} else {
+					post.Likes.Add(-1)
+				}
+			}
+			view = newView(post, s)
+		})
+		ex14Tmpl.ExecuteTemplate(w, "post", view)
+	}))
+
+	// Stands in for other visitors liking at the same time. The crowd's likes stay in
+	// the session, so nobody else sees them and Reset takes them back.
+	mux.HandleFunc("POST /exercise14/crowd", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex14Store.with(w, r, func(s *ex14State) { s.Crowd += 5 })
+		w.Write([]byte(`<span class="small text-muted">+5 likes from other visitors on every post</span>`))
+	}))
+
+	mux.HandleFunc("GET /exercise14/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		// Take back this visitor's likes; everyone else's stay. Both happen under
+		// one lock, so a like can't land between the take-back and the reset.
+		var views []ex14View
+		ex14Store.with(w, r, func(s *ex14State) {
+			for _, p := range ex14Posts {
+				if s.Liked[p.ID] {
+					p.Likes.Add(-1)
+				}
+			}
+			*s = ex14State{Liked: map[int]bool{}}
+			for _, p := range ex14Posts {
+				views = append(views, newView(p, s))
+			}
+		})
+		ex14Tmpl.ExecuteTemplate(w, "demo", map[string]interface{}{
+			"Posts":    views,
+			"CrowdURL": endpoint("/exercise14/crowd"),
+		})
+	}))
+}
+
+var ex14Tmpl = template.Must(template.New("exercise14").Parse(`
+{{define "post"}}<div id="ex14-post-{{.ID}}" class="list-group-item d-flex justify-content-between align-items-center"
+     hx-get="{{.LikeURL}}" hx-trigger="every 3s" hx-swap="outerHTML">
+    <span class="small">{{.Title}}</span>
+    <button class="btn btn-sm {{if .Liked}}btn-danger{{else}}btn-outline-danger{{end}}"
+            hx-post="{{.LikeURL}}" hx-vals='{"action": "{{if .Liked}}unlike{{else}}like{{end}}"}'
+            hx-target="closest .list-group-item" hx-swap="outerHTML" hx-sync="closest .list-group-item:replace"
+            hx-on:htmx:before-request="ex14Optimistic(this)">♥ <span class="ex14-count">{{.Likes}}</span></button>
+</div>{{end}}
+
+{{define "demo"}}<div class="list-group mb-3">
+    {{range .Posts}}{{template "post" .}}{{end}}
+</div>
+<button class="btn btn-sm btn-outline-secondary" hx-post="{{.CrowdURL}}" hx-target="#ex14-note">Simulate other visitors</button>
+<span id="ex14-note"></span>{{end}}
+`))
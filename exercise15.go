@@ -0,0 +1,206 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 15: Kanban Board
+// ----------------------------------------------------------------------------------
+// A bigger example that puts several earlier exercises together: drag and drop
+// (exercise 8), inline forms (exercise 5) and per-session state. Every change
+// answers with the card it touched plus out-of-band updates for the column counts.
+
+type ex15Column struct {
+	Key   string
+	Title string
+}
+
+var ex15Columns = []ex15Column{
+	{Key: "todo", Title: "To Do"},
+	{Key: "doing", Title: "Doing"},
+	{Key: "done", Title: "Done"},
+}
+
+type ex15Card struct {
+	ID     int
+	Title  string
+	Column string
+}
+
+type ex15State struct {
+	NextID int
+	Cards  map[string][]ex15Card // column key -> cards, top to bottom
+}
+
+func newEx15State() *ex15State {
+	return &ex15State{
+		NextID: 4,
+		Cards: map[string][]ex15Card{
+			"todo":  {{ID: 1, Title: "Write the handlers", Column: "todo"}, {ID: 2, Title: "Add OOB swaps", Column: "todo"}},
+			"doing": {{ID: 3, Title: "Learn HTMX", Column: "doing"}},
+		},
+	}
+}
+
+var ex15Store = newSessionStore(newEx15State)
+
+// remove takes the card out of whichever column holds it.
+func (s *ex15State) remove(id int) (ex15Card, bool) {
+	for col, cards := range s.Cards {
+		for i, c := range cards {
+			if c.ID == id {
+				s.Cards[col] = slices.Delete(cards, i, i+1)
+				return c, true
+			}
+		}
+	}
+	return ex15Card{}, false
+}
+
+type ex15CardView struct {
+	ex15Card
+	MoveURL   string
+	DeleteURL string
+}
+
+type ex15ColumnView struct {
+	ex15Column
+	Cards []ex15CardView
+	OOB   bool // render the count as an out-of-band swap
+}
+
+type ex15View struct {
+	Columns  []ex15ColumnView
+	CardsURL string
+}
+
+func addExercise15Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	newCardView := func(c ex15Card) ex15CardView {
+		id := strconv.Itoa(c.ID)
+		return ex15CardView{
+			ex15Card:  c,
+			MoveURL:   endpoint("/exercise15/move?id=" + id),
+			DeleteURL: endpoint("/exercise15/cards?id=" + id),
+		}
+	}
+	// Columns with their cards; oob marks the counts for out-of-band swapping
+	newColumns := func(s *ex15State, oob bool) []ex15ColumnView {
+		var columns []ex15ColumnView
+		for _, col := range ex15Columns {
+			view := ex15ColumnView{ex15Column: col, OOB: oob}
+			for _, c := range s.Cards[col.Key] {
+				view.Cards = append(view.Cards, newCardView(c))
+			}
+			columns = append(columns, view)
+		}
+		return columns
+	}
+	validColumn := func(key string) bool {
+		return slices.ContainsFunc(ex15Columns, func(c ex15Column) bool { return c.Key == key })
+	}
+
+	// Adds a card to a column
+	mux.HandleFunc("POST /exercise15/cards", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		column := r.PostFormValue("column")
+		title := strings.TrimSpace(r.PostFormValue("title"))
+		if !validColumn(column) || title == "" {
+			http.Error(w, "a column and a title are required", http.StatusBadRequest)
+			return
+		}
+		var card ex15Card
+		var columns []ex15ColumnView
+		ex15Store.with(w, r, func(s *ex15State) {
+			card = ex15Card{ID: s.NextID, Title: title, Column: column}
+			s.NextID++
+			s.Cards[column] = append(s.Cards[column], card)
+			columns = newColumns(s, true)
+		})
+		// Appended to the column by hx-swap="beforeend"
+		ex15Tmpl.ExecuteTemplate(w, "card", newCardView(card))
+		ex15Tmpl.ExecuteTemplate(w, "counts", columns)
+	}))
+	// Removes a card. No main content: the outerHTML swap removes it from the page
+	mux.HandleFunc("DELETE /exercise15/cards", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+		var columns []ex15ColumnView
+		ex15Store.with(w, r, func(s *ex15State) {
+			s.remove(id)
+			columns = newColumns(s, true)
+		})
+		ex15Tmpl.ExecuteTemplate(w, "counts", columns)
+	}))
+
+	mux.HandleFunc("POST /exercise15/move", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+		column := r.PostFormValue("column")
+		index, _ := strconv.Atoi(r.PostFormValue("index"))
+		if !validColumn(column) {
+			http.Error(w, "unknown column", http.StatusBadRequest)
+			return
+		}
+
+		var card ex15Card
+		var columns []ex15ColumnView
+		found := false
+		ex15Store.with(w, r, func(s *ex15State) {
+			if card, found = s.remove(id); !found {
+				return
+			}
+			card.Column = column
+			cards := s.Cards[column]
+			index = max(0, min(index, len(cards)))
+			s.Cards[column] = slices.Insert(cards, index, card)
+			columns = newColumns(s, true)
+		})
+		if !found {
+			http.Error(w, "unknown card", http.StatusNotFound)
+			return
+		}
+
+		// The card is already where it was dropped; re-render it for its new column
+		ex15Tmpl.ExecuteTemplate(w, "card", newCardView(card))
+		ex15Tmpl.ExecuteTemplate(w, "counts", columns)
+	}))
+
+	mux.HandleFunc("GET /exercise15/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex15Store.reset(w, r)
+		ex15Tmpl.ExecuteTemplate(w, "board", ex15View{
+			Columns:  newColumns(newEx15State(), false),
+			CardsURL: endpoint("/exercise15/cards"),
+		})
+	}))
+}
+
+var ex15Tmpl = template.Must(template.New("exercise15").Parse(`
+{{define "card"}}<div class="card card-body p-2 mb-2 small ex15-card"
+     hx-post="{{.MoveURL}}" hx-trigger="moved" hx-vals='js:{column: event.detail.column, index: event.detail.index}' hx-swap="outerHTML">
+    <div class="d-flex justify-content-between align-items-start">
+        <span{{if eq .Column "done"}} class="text-decoration-line-through text-muted"{{end}}>{{.Title}}</span>
+        <button class="btn-close" aria-label="Delete" hx-delete="{{.DeleteURL}}" hx-target="closest .ex15-card" hx-swap="outerHTML"></button>
+    </div>
+</div>{{end}}
+
+{{define "count"}}<span id="ex15-count-{{.Key}}" class="badge bg-secondary"{{if .OOB}} hx-swap-oob="true"{{end}}>{{len .Cards}}</span>{{end}}
+
+{{define "counts"}}{{range .}}{{template "count" .}}{{end}}{{end}}
+
+{{define "board"}}<div class="row g-2">
+    {{range .Columns}}<div class="col">
+        <div class="bg-light border rounded p-2 h-100">
+            <div class="d-flex justify-content-between mb-2"><strong class="small">{{.Title}}</strong>{{template "count" .}}</div>
+            <div id="ex15-col-{{.Key}}" class="ex15-column" data-column="{{.Key}}">
+                {{range .Cards}}{{template "card" .}}{{end}}
+            </div>
+            <form hx-post="{{$.CardsURL}}" hx-target="#ex15-col-{{.Key}}" hx-swap="beforeend" hx-on:htmx:after-request="if (event.detail.successful) this.reset()">
+                <input type="hidden" name="column" value="{{.Key}}">
+                <input type="text" name="title" class="form-control form-control-sm" placeholder="+ Add card" required>
+            </form>
+        </div>
+    </div>{{end}}
+</div>{{end}}
+`))
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 16: What Gets Sent
+// ----------------------------------------------------------------------------------
+// An echo endpoint that shows exactly which parameters and HX-* headers arrived,
+// so the effect of hx-vals, hx-include and hx-params can be checked directly.
+
+type ex16Pair struct {
+	Name  string
+	Value string
+}
+
+type ex16Echo struct {
+	Method  string
+	Query   []ex16Pair
+	Form    []ex16Pair
+	Headers []ex16Pair
+}
+
+// Flatten url.Values into sorted name/value rows, one row per value
+func ex16Pairs(values url.Values) []ex16Pair {
+	var pairs []ex16Pair
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		for _, v := range values[name] {
+			pairs = append(pairs, ex16Pair{name, v})
+		}
+	}
+	return pairs
+}
+
+func addExercise16Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	// Echoes whatever arrives, so the demo can send it with either method
+	handleEcho := corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		echo := ex16Echo{
+			Method: r.Method,
+			Query:  ex16Pairs(r.URL.Query()),
+			Form:   ex16Pairs(r.PostForm),
+		}
+		hx := url.Values{}
+		for name, values := range r.Header {
+			// Go canonicalizes header names to "Hx-Request"; show them the way HTMX spells them
+			if rest, ok := strings.CutPrefix(name, "Hx-"); ok {
+				hx["HX-"+rest] = values
+			}
+		}
+		echo.Headers = ex16Pairs(hx)
+
+		ex16Tmpl.Execute(w, echo)
+	})
+	mux.HandleFunc("GET /exercise16/echo", handleEcho)
+	mux.HandleFunc("POST /exercise16/echo", handleEcho)
+	mux.HandleFunc("GET /exercise16/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "")
+	}))
+}
+
+var ex16Tmpl = template.Must(template.New("exercise16").Parse(`<table class="table table-sm small mb-0">
+    <tr class="table-light"><th colspan="2">{{.Method}} request</th></tr>
+    <tr><th colspan="2" class="text-muted fw-normal">Query parameters</th></tr>
+    {{range .Query}}<tr><td><code>{{.Name}}</code></td><td>{{.Value}}</td></tr>{{else}}<tr><td colspan="2" class="text-muted">none</td></tr>{{end}}
+    <tr><th colspan="2" class="text-muted fw-normal">Form fields</th></tr>
+    {{range .Form}}<tr><td><code>{{.Name}}</code></td><td>{{.Value}}</td></tr>{{else}}<tr><td colspan="2" class="text-muted">none</td></tr>{{end}}
+    <tr><th colspan="2" class="text-muted fw-normal">HX-* headers</th></tr>
+    {{range .Headers}}<tr><td><code>{{.Name}}</code></td><td>{{.Value}}</td></tr>{{else}}<tr><td colspan="2" class="text-muted">none</td></tr>{{end}}
+</table>`))
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 17: Picking Fragments
+// ----------------------------------------------------------------------------------
+// The article endpoint can always send a complete HTML page, and the browser uses
+// hx-select and hx-select-oob to keep only the parts it wants. The alternative is
+// to decide on the server: the page template is built from named {{block}}s, so an
+// HTMX request (HX-Request: true) gets just the "article" block and a normal visit
+// gets the whole page.
+
+type ex17Article struct {
+	Title string
+	Body  string
+}
+
+var ex17Articles = []ex17Article{
+	{Title: "Fragments, not JSON", Body: "An HTMX endpoint answers with HTML. The server already knows how to render the page, so it can render a piece of it just as easily, and the browser swaps that piece in without any client-side templates."},
+	{Title: "One template, many views", Body: "A {{block}} defines a named template and uses it in place. Executing the outer template renders the full page, while ExecuteTemplate with the block's name renders only that block. Both come from the same markup, so they never drift apart."},
+	{Title: "Let the client choose", Body: "When the server can't or won't send a fragment, hx-select picks the part of the response to swap and hx-select-oob pulls out other elements to update elsewhere on the page. The cost is transferring the whole page every time."},
+}
+
+type ex17Link struct {
+	Number int
+	URL    string
+}
+
+type ex17View struct {
+	ex17Article
+	Number int
+	Total  int
+	Words  int
+	Full   bool // the whole page was rendered
+	Links  []ex17Link
+}
+
+// Full page for normal visits and ?full=1; only the article block for HTMX requests
+func ex17WantsFullPage(r *http.Request) bool {
+	return r.URL.Query().Get("full") == "1" || r.Header.Get("HX-Request") != "true"
+}
+
+func addExercise17Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	pageURL := func(number int, full bool) string {
+		url := "/exercise17/article?page=" + strconv.Itoa(number)
+		if full {
+			url += "&full=1"
+		}
+		return endpoint(url)
+	}
+
+	mux.HandleFunc("GET /exercise17/article", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		number, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil || number < 1 || number > len(ex17Articles) {
+			http.Error(w, "unknown page", http.StatusNotFound)
+			return
+		}
+
+		view := ex17View{
+			ex17Article: ex17Articles[number-1],
+			Number:      number,
+			Total:       len(ex17Articles),
+			Words:       len(strings.Fields(ex17Articles[number-1].Body)),
+			Full:        ex17WantsFullPage(r),
+		}
+		// Links keep asking for the same kind of response as this one
+		for i := range ex17Articles {
+			view.Links = append(view.Links, ex17Link{Number: i + 1, URL: pageURL(i+1, r.URL.Query().Get("full") == "1")})
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if view.Full {
+			ex17Tmpl.ExecuteTemplate(w, "page", view)
+		} else {
+			ex17Tmpl.ExecuteTemplate(w, "article", view)
+		}
+	}))
+
+	mux.HandleFunc("GET /exercise17/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		var selectLinks, blockLinks []ex17Link
+		for i := range ex17Articles {
+			selectLinks = append(selectLinks, ex17Link{Number: i + 1, URL: pageURL(i+1, true)})
+			blockLinks = append(blockLinks, ex17Link{Number: i + 1, URL: pageURL(i+1, false)})
+		}
+		ex17Tmpl.ExecuteTemplate(w, "demo", map[string]interface{}{
+			"SelectLinks": selectLinks,
+			"BlockLinks":  blockLinks,
+		})
+	}))
+}
+
+// "page" is a complete document. Each {{block}} inside it is also a template of its
+// own, which is what the HTMX branch of the handler executes.
+var ex17Tmpl = template.Must(template.New("exercise17").Parse(`
+{{define "page"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>{{block "title" .}}{{.Title}} · Go Notes{{end}}</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body>
+<div class="container my-4">
+    <header class="border-bottom pb-2 mb-3"><strong>Go Notes</strong> <span class="text-muted small">a full page, exactly as a browser would get it</span></header>
+    <div class="row">
+        <main class="col-md-8">{{block "article" .}}<article class="ex17-article">
+            <h5>{{.Title}}</h5>
+            <p class="small">{{.Body}}</p>
+            <nav class="small">Page: {{range .Links}}<a href="{{.URL}}" hx-get="{{.URL}}" class="me-2">{{.Number}}</a>{{end}}</nav>
+        </article>{{end}}</main>
+        <aside class="col-md-4">{{block "stats" .}}<div id="ex17-stats" class="small text-muted border rounded p-2">
+            Page {{.Number}} of {{.Total}} · {{.Words}} words · sent as {{if .Full}}a full page{{else}}a single block{{end}}
+        </div>{{end}}</aside>
+    </div>
+    <footer class="border-top mt-3 pt-2 small text-muted">Everything outside the article is what hx-select throws away.</footer>
+</div>
+</body>
+</html>{{end}}
+
+{{define "demo"}}<div class="small text-muted mb-1"><code>hx-select</code>: the server sends the whole page</div>
+<div hx-target="#ex17-select-out" hx-select="article" hx-select-oob="#ex17-stats">
+    <div class="btn-group btn-group-sm mb-2">{{range .SelectLinks}}<button class="btn btn-outline-primary" hx-get="{{.URL}}">Page {{.Number}}</button>{{end}}</div>
+    <div id="ex17-select-out" class="mb-2"></div>
+</div>
+<div id="ex17-stats" class="small text-muted border rounded p-2 mb-3">Stats arrive with hx-select-oob</div>
+
+<div class="small text-muted mb-1"><code>{{"{{block}}"}}</code>: the server sends only the article</div>
+<div hx-target="#ex17-block-out">
+    <div class="btn-group btn-group-sm mb-2">{{range .BlockLinks}}<button class="btn btn-outline-success" hx-get="{{.URL}}">Page {{.Number}}</button>{{end}}</div>
+    <div id="ex17-block-out" class="mb-2"></div>
+</div>
+
+<pre id="ex17-log" class="small bg-light border rounded p-2 mb-2"></pre>
+{{with index .SelectLinks 0}}<a href="{{.URL}}" target="_blank" class="small">Open the full page in a new tab</a>{{end}}{{end}}
+`))
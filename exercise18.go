@@ -0,0 +1,299 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 18: Multi-Step Wizard
+// ----------------------------------------------------------------------------------
+// The exercise 5 form grown into three steps. Each step posts only its own fields;
+// the server remembers everything entered so far in the session, checks the step,
+// and answers with the next one plus an HX-Push-Url header so the address bar and
+// the Back button follow along. Because the values live on the server, going back
+// re-renders a step with what was typed before.
+
+type ex18Field struct {
+	Name  string
+	Label string
+	Type  string
+	Check func(value string) string // error message, "" when valid
+}
+
+type ex18Step struct {
+	Title  string
+	Fields []ex18Field
+}
+
+func ex18Required(label string) func(string) string {
+	return func(value string) string {
+		if value == "" {
+			return label + " is required."
+		}
+		return ""
+	}
+}
+
+func ex18CheckEmail(value string) string {
+	if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+		return "That doesn't look like an email address."
+	}
+	return ""
+}
+
+func ex18CheckPostcode(value string) string {
+	digits := 0
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == ' ' || r == '-' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z'):
+		default:
+			return "Use only letters, digits, spaces and dashes."
+		}
+	}
+	if len(value) < 3 || len(value) > 10 || digits == 0 {
+		return "A postcode has 3 to 10 characters, including at least one digit."
+	}
+	return ""
+}
+
+var ex18Steps = []ex18Step{
+	{Title: "Account", Fields: []ex18Field{
+		{Name: "name", Label: "Full name", Type: "text", Check: ex18Required("Your name")},
+		{Name: "email", Label: "Email", Type: "email", Check: ex18CheckEmail},
+	}},
+	{Title: "Shipping", Fields: []ex18Field{
+		{Name: "street", Label: "Street", Type: "text", Check: ex18Required("The street")},
+		{Name: "city", Label: "City", Type: "text", Check: ex18Required("The city")},
+		{Name: "postcode", Label: "Postcode", Type: "text", Check: ex18CheckPostcode},
+	}},
+	{Title: "Confirm", Fields: []ex18Field{
+		{Name: "terms", Label: "I agree to the terms", Type: "checkbox", Check: func(value string) string {
+			if value != "on" {
+				return "Please accept the terms to place the order."
+			}
+			return ""
+		}},
+	}},
+}
+
+type ex18State struct {
+	Values  map[string]string // every field entered so far, valid or not
+	Reached int               // furthest step the visitor may open
+}
+
+func newEx18State() *ex18State {
+	return &ex18State{Values: map[string]string{}, Reached: 1}
+}
+
+var ex18Store = newSessionStore(newEx18State)
+
+type ex18FieldView struct {
+	ex18Field
+	Value string
+	Error string
+}
+
+type ex18ProgressItem struct {
+	Number int
+	Title  string
+	State  string // "done", "current" or "todo"
+}
+
+type ex18View struct {
+	Progress []ex18ProgressItem
+	Fields   []ex18FieldView
+	Review   []ex18FieldView // on the last step: the earlier answers
+	Last     bool
+	StepURL  string
+	BackURL  string // empty on the first step
+	ResetURL string
+}
+
+// ex18StepNumber reads the step from ?n=, reporting whether it names a step.
+func ex18StepNumber(r *http.Request) (int, bool) {
+	n, err := strconv.Atoi(r.URL.Query().Get("n"))
+	return n, err == nil && n >= 1 && n <= len(ex18Steps)
+}
+
+// firstInvalidStep checks every saved answer and returns the number of the first
+// step with a problem along with its errors, or 0 if all are valid.
+func firstInvalidStep(s *ex18State) (int, map[string]string) {
+	for i, step := range ex18Steps {
+		errors := map[string]string{}
+		for _, f := range step.Fields {
+			if msg := f.Check(s.Values[f.Name]); msg != "" {
+				errors[f.Name] = msg
+			}
+		}
+		if len(errors) > 0 {
+			return i + 1, errors
+		}
+	}
+	return 0, nil
+}
+
+func addExercise18Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	stepURL := func(n int) string {
+		return endpoint("/exercise18/step?n=" + strconv.Itoa(n))
+	}
+	newView := func(s *ex18State, n int, errors map[string]string) ex18View {
+		view := ex18View{
+			Last:     n == len(ex18Steps),
+			StepURL:  stepURL(n),
+			ResetURL: endpoint("/exercise18/reset"),
+		}
+		for i, step := range ex18Steps {
+			item := ex18ProgressItem{Number: i + 1, Title: step.Title, State: "todo"}
+			switch {
+			case i+1 == n:
+				item.State = "current"
+			case i+1 < s.Reached:
+				item.State = "done"
+			}
+			view.Progress = append(view.Progress, item)
+		}
+		if n > 1 {
+			view.BackURL = stepURL(n - 1)
+		}
+		for _, f := range ex18Steps[n-1].Fields {
+			view.Fields = append(view.Fields, ex18FieldView{f, s.Values[f.Name], errors[f.Name]})
+		}
+		if view.Last {
+			for _, step := range ex18Steps[:n-1] {
+				for _, f := range step.Fields {
+					view.Review = append(view.Review, ex18FieldView{ex18Field: f, Value: s.Values[f.Name]})
+				}
+			}
+		}
+		return view
+	}
+
+	// Shows a step with the saved values
+	mux.HandleFunc("GET /exercise18/step", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		n, ok := ex18StepNumber(r)
+		if !ok {
+			http.Error(w, "unknown step", http.StatusNotFound)
+			return
+		}
+		// A reload of a pushed URL, or a history cache miss: the wizard only
+		// exists inside the tutorial page, so send the browser there
+		if r.Header.Get("HX-Request") != "true" || r.Header.Get("HX-History-Restore-Request") == "true" {
+			http.Redirect(w, r, "/", http.StatusSeeOther)
+			return
+		}
+
+		var view ex18View
+		ex18Store.with(w, r, func(s *ex18State) {
+			// Steps after the furthest valid one can't be opened by URL
+			view = newView(s, min(n, s.Reached), nil)
+		})
+		ex18Tmpl.ExecuteTemplate(w, "wizard", view)
+	}))
+
+	// Submits a step and answers with the next one, or the same one with errors
+	mux.HandleFunc("POST /exercise18/step", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		n, ok := ex18StepNumber(r)
+		if !ok {
+			http.Error(w, "unknown step", http.StatusNotFound)
+			return
+		}
+
+		var view ex18View
+		done := false
+		ex18Store.with(w, r, func(s *ex18State) {
+			// Steps after the furthest valid one can't be submitted by URL
+			if n > s.Reached {
+				view = newView(s, s.Reached, nil)
+				return
+			}
+			errors := map[string]string{}
+			for _, f := range ex18Steps[n-1].Fields {
+				value := strings.TrimSpace(r.PostFormValue(f.Name))
+				s.Values[f.Name] = value
+				if msg := f.Check(value); msg != "" {
+					errors[f.Name] = msg
+				}
+			}
+			if len(errors) > 0 {
+				view = newView(s, n, errors)
+				return
+			}
+			if n == len(ex18Steps) {
+				// Earlier answers may never have been checked, e.g. when the
+				// steps were posted out of order; send the visitor back to the
+				// first step that still has a problem
+				if i, errors := firstInvalidStep(s); i > 0 {
+					s.Reached = i
+					w.Header().Set("HX-Push-Url", stepURL(i))
+					view = newView(s, i, errors)
+					return
+				}
+				done = true
+				view = newView(s, n, nil)
+				return
+			}
+			n++
+			s.Reached = max(s.Reached, n)
+			w.Header().Set("HX-Push-Url", stepURL(n))
+			view = newView(s, n, nil)
+		})
+
+		if done {
+			ex18Tmpl.ExecuteTemplate(w, "done", view)
+			return
+		}
+		ex18Tmpl.ExecuteTemplate(w, "wizard", view)
+	}))
+
+	mux.HandleFunc("GET /exercise18/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex18Store.reset(w, r)
+		ex18Tmpl.ExecuteTemplate(w, "wizard", newView(newEx18State(), 1, nil))
+	}))
+}
+
+// The wrapper re-fetches its step after Back/Forward: the history snapshot only has
+// the markup, not what was typed into it.
+var ex18Tmpl = template.Must(template.New("exercise18").Parse(`
+{{define "progress"}}<div class="d-flex gap-1 small mb-3">
+    {{range .Progress}}<span class="badge {{if eq .State "current"}}bg-primary{{else if eq .State "done"}}bg-success{{else}}bg-light text-muted border{{end}}">{{.Number}}. {{.Title}}</span>{{end}}
+</div>{{end}}
+
+{{define "field"}}{{if eq .Type "checkbox"}}<div class="form-check mb-2">
+    <input type="checkbox" id="ex18-{{.Name}}" name="{{.Name}}" class="form-check-input{{if .Error}} is-invalid{{end}}"{{if eq .Value "on"}} checked{{end}}>
+    <label for="ex18-{{.Name}}" class="form-check-label small">{{.Label}}</label>
+    {{if .Error}}<div class="invalid-feedback">{{.Error}}</div>{{end}}
+</div>{{else}}<div class="mb-2">
+    <label for="ex18-{{.Name}}" class="form-label small mb-1">{{.Label}}</label>
+    <input type="{{.Type}}" id="ex18-{{.Name}}" name="{{.Name}}" class="form-control form-control-sm{{if .Error}} is-invalid{{end}}" value="{{.Value}}">
+    {{if .Error}}<div class="invalid-feedback">{{.Error}}</div>{{end}}
+</div>{{end}}{{end}}
+
+{{define "review"}}<dl class="row small mb-2">
+    {{range .}}<dt class="col-4 fw-normal text-muted">{{.Label}}</dt><dd class="col-8 mb-1">{{.Value}}</dd>{{end}}
+</dl>{{end}}
+
+{{define "wizard"}}<div id="ex18-wizard" hx-get="{{.StepURL}}" hx-trigger="htmx:historyRestore from:body" hx-swap="outerHTML">
+    {{template "progress" .}}
+    <form hx-post="{{.StepURL}}" hx-target="#ex18-wizard" hx-swap="outerHTML" novalidate>
+        {{if .Review}}{{template "review" .Review}}{{end}}
+        {{range .Fields}}{{template "field" .}}{{end}}
+        <div class="d-flex gap-2 mt-3">
+            {{if .BackURL}}<button type="button" class="btn btn-sm btn-outline-secondary"
+                    hx-get="{{.BackURL}}" hx-target="#ex18-wizard" hx-swap="outerHTML" hx-push-url="true">← Back</button>{{end}}
+            <button type="submit" class="btn btn-sm btn-success">{{if .Last}}Place order{{else}}Next →{{end}}</button>
+        </div>
+    </form>
+</div>{{end}}
+
+{{define "done"}}<div id="ex18-wizard">
+    <div class="alert alert-success small py-2">Order placed! It ships to:</div>
+    {{template "review" .Review}}
+    <button class="btn btn-sm btn-outline-secondary" hx-get="{{.ResetURL}}" hx-target="#ex18-wizard" hx-swap="outerHTML">Start over</button>
+</div>{{end}}
+`))
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"cmp"
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 19: Data Table
+// ----------------------------------------------------------------------------------
+// Sorting, filtering, paging and hiding columns all happen on the server. Everything
+// the table depends on is in the query string, parsed into a TableQuery, so any
+// state of the table has a URL. hx-replace-url keeps the address bar on that URL,
+// and opening it directly renders the same table as a standalone page.
+
+type ex19Language struct {
+	Name    string
+	Year    int
+	Typing  string
+	Creator string
+}
+
+var ex19Languages = []ex19Language{
+	{"Ada", 1980, "static", "Jean Ichbiah"},
+	{"C", 1972, "static", "Dennis Ritchie"},
+	{"C#", 2000, "static", "Anders Hejlsberg"},
+	{"C++", 1985, "static", "Bjarne Stroustrup"},
+	{"Clojure", 2007, "dynamic", "Rich Hickey"},
+	{"COBOL", 1959, "static", "CODASYL"},
+	{"Dart", 2011, "static", "Lars Bak"},
+	{"Elixir", 2012, "dynamic", "José Valim"},
+	{"Erlang", 1986, "dynamic", "Joe Armstrong"},
+	{"F#", 2005, "static", "Don Syme"},
+	{"Fortran", 1957, "static", "John Backus"},
+	{"Go", 2009, "static", "Robert Griesemer"},
+	{"Haskell", 1990, "static", "Simon Peyton Jones"},
+	{"Java", 1995, "static", "James Gosling"},
+	{"JavaScript", 1995, "dynamic", "Brendan Eich"},
+	{"Julia", 2012, "dynamic", "Jeff Bezanson"},
+	{"Kotlin", 2011, "static", "Andrey Breslav"},
+	{"Lisp", 1958, "dynamic", "John McCarthy"},
+	{"Lua", 1993, "dynamic", "Roberto Ierusalimschy"},
+	{"OCaml", 1996, "static", "Xavier Leroy"},
+	{"Pascal", 1970, "static", "Niklaus Wirth"},
+	{"Perl", 1987, "dynamic", "Larry Wall"},
+	{"PHP", 1995, "dynamic", "Rasmus Lerdorf"},
+	{"Python", 1991, "dynamic", "Guido van Rossum"},
+	{"R", 1993, "dynamic", "Ross Ihaka"},
+	{"Ruby", 1995, "dynamic", "Yukihiro Matsumoto"},
+	{"Rust", 2010, "static", "Graydon Hoare"},
+	{"Scala", 2004, "static", "Martin Odersky"},
+	{"Smalltalk", 1972, "dynamic", "Alan Kay"},
+	{"Swift", 2014, "static", "Chris Lattner"},
+	{"TypeScript", 2012, "static", "Anders Hejlsberg"},
+	{"Zig", 2016, "static", "Andrew Kelley"},
+}
+
+type ex19Column struct {
+	Key     string // value of the "sort" and "hide" parameters
+	Title   string
+	Compare func(a, b ex19Language) int
+	Value   func(l ex19Language) string
+}
+
+var ex19Columns = []ex19Column{
+	{"name", "Name", func(a, b ex19Language) int { return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }, func(l ex19Language) string { return l.Name }},
+	{"year", "Year", func(a, b ex19Language) int { return cmp.Compare(a.Year, b.Year) }, func(l ex19Language) string { return strconv.Itoa(l.Year) }},
+	{"typing", "Typing", func(a, b ex19Language) int { return cmp.Compare(a.Typing, b.Typing) }, func(l ex19Language) string { return l.Typing }},
+	{"creator", "Creator", func(a, b ex19Language) int { return cmp.Compare(a.Creator, b.Creator) }, func(l ex19Language) string { return l.Creator }},
+}
+
+func ex19FindColumn(key string) (ex19Column, bool) {
+	i := slices.IndexFunc(ex19Columns, func(c ex19Column) bool { return c.Key == key })
+	if i < 0 {
+		return ex19Column{}, false
+	}
+	return ex19Columns[i], true
+}
+
+var ex19PageSizes = []int{5, 10, 20}
+
+// TableQuery is everything the table view depends on. It is parsed from the URL
+// and written back to it, so both always agree.
+type TableQuery struct {
+	Sort    string // a column key
+	Desc    bool
+	Name    string // substring filters, case insensitive
+	Creator string
+	Typing  string // "", "static" or "dynamic"
+	Page    int    // 1-based
+	PerPage int
+	Hide    []string // keys of hidden columns, in column order
+}
+
+// Shown reports whether the column with the given key is visible.
+func (q TableQuery) Shown(key string) bool {
+	return !slices.Contains(q.Hide, key)
+}
+
+var defaultTableQuery = TableQuery{Sort: "name", Page: 1, PerPage: 10}
+
+// parseTableQuery reads a TableQuery from URL parameters. Anything missing or
+// invalid falls back to the default, so every URL shows some table.
+func parseTableQuery(values url.Values) TableQuery {
+	q := defaultTableQuery
+	if _, ok := ex19FindColumn(values.Get("sort")); ok {
+		q.Sort = values.Get("sort")
+	}
+	q.Desc = values.Get("dir") == "desc"
+	q.Name = strings.TrimSpace(values.Get("name"))
+	q.Creator = strings.TrimSpace(values.Get("creator"))
+	if typing := values.Get("typing"); typing == "static" || typing == "dynamic" {
+		q.Typing = typing
+	}
+	if page, err := strconv.Atoi(values.Get("page")); err == nil && page > 1 {
+		q.Page = page
+	}
+	if perPage, err := strconv.Atoi(values.Get("per_page")); err == nil && slices.Contains(ex19PageSizes, perPage) {
+		q.PerPage = perPage
+	}
+	for _, col := range ex19Columns {
+		if slices.Contains(values["hide"], col.Key) {
+			q.Hide = append(q.Hide, col.Key)
+		}
+	}
+	if len(q.Hide) == len(ex19Columns) {
+		q.Hide = nil // a table needs at least one column
+	}
+	return q
+}
+
+// Values is the inverse of parseTableQuery, leaving out defaults to keep URLs short.
+func (q TableQuery) Values() url.Values {
+	values := url.Values{}
+	set := func(name, value, def string) {
+		if value != def {
+			values.Set(name, value)
+		}
+	}
+	set("sort", q.Sort, defaultTableQuery.Sort)
+	if q.Desc {
+		values.Set("dir", "desc")
+	}
+	set("name", q.Name, "")
+	set("creator", q.Creator, "")
+	set("typing", q.Typing, "")
+	set("page", strconv.Itoa(q.Page), "1")
+	set("per_page", strconv.Itoa(q.PerPage), strconv.Itoa(defaultTableQuery.PerPage))
+	for _, key := range q.Hide {
+		values.Add("hide", key)
+	}
+	return values
+}
+
+// apply filters and sorts the rows and cuts out the requested page. It also moves
+// q.Page back to the last page when the filters left fewer rows than it needs.
+func (q *TableQuery) apply(rows []ex19Language) (page []ex19Language, matching int) {
+	var filtered []ex19Language
+	for _, row := range rows {
+		if strings.Contains(strings.ToLower(row.Name), strings.ToLower(q.Name)) &&
+			strings.Contains(strings.ToLower(row.Creator), strings.ToLower(q.Creator)) &&
+			(q.Typing == "" || row.Typing == q.Typing) {
+			filtered = append(filtered, row)
+		}
+	}
+
+	column, _ := ex19FindColumn(q.Sort)
+	slices.SortStableFunc(filtered, func(a, b ex19Language) int {
+		if q.Desc {
+			return column.Compare(b, a)
+		}
+		return column.Compare(a, b)
+	})
+
+	pages := max(1, (len(filtered)+q.PerPage-1)/q.PerPage)
+	q.Page = min(q.Page, pages)
+	start := (q.Page - 1) * q.PerPage
+	return filtered[start:min(start+q.PerPage, len(filtered))], len(filtered)
+}
+
+type ex19Header struct {
+	Key    string
+	Title  string
+	Sorted bool
+	Desc   bool
+	URL    string // sorts by this column, or flips the direction if it already does
+}
+
+type ex19Toggle struct {
+	Title    string
+	Shown    bool
+	Disabled bool   // the last visible column can't be hidden
+	URL      string // the same table with this column shown or hidden
+}
+
+type ex19PageLink struct {
+	Number  int
+	Current bool
+	URL     string
+}
+
+type ex19View struct {
+	Query     TableQuery
+	Headers   []ex19Header // visible columns only
+	Toggles   []ex19Toggle
+	Rows      [][]string // cells of the visible columns
+	Matching  int
+	First     int // 1-based positions of the rows shown
+	Last      int
+	Pages     []ex19PageLink
+	PageSizes []int
+	TableURL  string
+}
+
+func addExercise19Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	tableURL := func(q TableQuery) string {
+		path := "/exercise19/table"
+		if query := q.Values().Encode(); query != "" {
+			path += "?" + query
+		}
+		return endpoint(path)
+	}
+	newView := func(q TableQuery) ex19View {
+		view := ex19View{PageSizes: ex19PageSizes, TableURL: endpoint("/exercise19/table")}
+		rows, matching := q.apply(ex19Languages)
+		view.Matching = matching
+		view.Query = q
+		if len(rows) > 0 {
+			view.First = (q.Page-1)*q.PerPage + 1
+			view.Last = view.First + len(rows) - 1
+		}
+
+		var shown []ex19Column
+		for _, col := range ex19Columns {
+			toggled := q
+			if q.Shown(col.Key) {
+				toggled.Hide = append(slices.Clone(q.Hide), col.Key)
+				shown = append(shown, col)
+			} else {
+				toggled.Hide = slices.DeleteFunc(slices.Clone(q.Hide), func(key string) bool { return key == col.Key })
+			}
+			// Round-trip through the URL to keep the keys in column order
+			toggled = parseTableQuery(toggled.Values())
+			view.Toggles = append(view.Toggles, ex19Toggle{
+				Title:    col.Title,
+				Shown:    q.Shown(col.Key),
+				Disabled: len(q.Hide) == len(ex19Columns)-1 && q.Shown(col.Key),
+				URL:      tableURL(toggled),
+			})
+			if !q.Shown(col.Key) {
+				continue
+			}
+			sorted := q
+			sorted.Sort, sorted.Desc, sorted.Page = col.Key, q.Sort == col.Key && !q.Desc, 1
+			view.Headers = append(view.Headers, ex19Header{
+				Key:    col.Key,
+				Title:  col.Title,
+				Sorted: q.Sort == col.Key,
+				Desc:   q.Desc,
+				URL:    tableURL(sorted),
+			})
+		}
+		for _, row := range rows {
+			var cells []string
+			for _, col := range shown {
+				cells = append(cells, col.Value(row))
+			}
+			view.Rows = append(view.Rows, cells)
+		}
+		for n := 1; n == 1 || (n-1)*q.PerPage < view.Matching; n++ {
+			paged := q
+			paged.Page = n
+			view.Pages = append(view.Pages, ex19PageLink{Number: n, Current: n == q.Page, URL: tableURL(paged)})
+		}
+		return view
+	}
+
+	mux.HandleFunc("GET /exercise19/table", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		view := newView(parseTableQuery(r.URL.Query()))
+		if r.Header.Get("HX-Request") != "true" {
+			// Opened directly, e.g. from a copied address: the same table as a page
+			ex19Tmpl.ExecuteTemplate(w, "page", view)
+			return
+		}
+		// hx-replace-url would use the request URL as sent, empty filters and all;
+		// the header replaces it with the canonical one
+		w.Header().Set("HX-Replace-Url", tableURL(view.Query))
+		ex19Tmpl.ExecuteTemplate(w, "table", view)
+	}))
+
+	mux.HandleFunc("GET /exercise19/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("HX-Replace-Url", endpoint("/"))
+		ex19Tmpl.ExecuteTemplate(w, "table", newView(defaultTableQuery))
+	}))
+}
+
+// The form reloads the table whenever a filter changes. Header and page buttons
+// carry complete URLs, and all of them inherit the target and hx-replace-url.
+var ex19Tmpl = template.Must(template.New("exercise19").Parse(`
+{{define "table"}}<form id="ex19-table" hx-get="{{.TableURL}}" hx-trigger="input delay:300ms, submit"
+      hx-target="this" hx-swap="outerHTML" hx-replace-url="true" hx-sync="this:replace">
+    <input type="hidden" name="sort" value="{{.Query.Sort}}">
+    {{if .Query.Desc}}<input type="hidden" name="dir" value="desc">{{end}}
+    {{- range .Query.Hide}}<input type="hidden" name="hide" value="{{.}}">{{end}}
+    {{- if not (.Query.Shown "name")}}<input type="hidden" name="name" value="{{.Query.Name}}">{{end}}
+    {{- if not (.Query.Shown "typing")}}<input type="hidden" name="typing" value="{{.Query.Typing}}">{{end}}
+    {{- if not (.Query.Shown "creator")}}<input type="hidden" name="creator" value="{{.Query.Creator}}">{{end}}
+    <div id="ex19-columns" class="d-flex flex-wrap align-items-center gap-1 small mb-2">
+        <span class="text-muted">Columns:</span>
+        {{range .Toggles}}<button type="button" class="btn btn-sm py-0 {{if .Shown}}btn-secondary{{else}}btn-outline-secondary{{end}}" aria-pressed="{{.Shown}}"{{if .Disabled}} disabled{{end}} hx-get="{{.URL}}">{{.Title}}</button>{{end}}
+    </div>
+    <table class="table table-sm table-hover small mb-2">
+        <thead>
+            <tr>{{range .Headers}}<th><button type="button" class="btn btn-link btn-sm p-0 text-decoration-none{{if not .Sorted}} text-body{{end}}"
+                hx-get="{{.URL}}">{{.Title}}{{if .Sorted}} {{if .Desc}}▼{{else}}▲{{end}}{{end}}</button></th>{{end}}</tr>
+            <tr>{{range .Headers}}
+                <th>{{if eq .Key "name"}}<input type="search" id="ex19-name" name="name" value="{{$.Query.Name}}" class="form-control form-control-sm" placeholder="Filter">
+                {{- else if eq .Key "typing"}}<select id="ex19-typing" name="typing" class="form-select form-select-sm">
+                    <option value="">Any</option>
+                    <option value="static"{{if eq $.Query.Typing "static"}} selected{{end}}>static</option>
+                    <option value="dynamic"{{if eq $.Query.Typing "dynamic"}} selected{{end}}>dynamic</option>
+                </select>
+                {{- else if eq .Key "creator"}}<input type="search" id="ex19-creator" name="creator" value="{{$.Query.Creator}}" class="form-control form-control-sm" placeholder="Filter">{{end}}</th>{{end}}
+            </tr>
+        </thead>
+        <tbody>
+            {{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+            {{else}}<tr><td colspan="{{len .Headers}}" class="text-muted text-center">No languages match.</td></tr>{{end}}
+        </tbody>
+    </table>
+    <div class="d-flex justify-content-between align-items-center small">
+        <span class="text-muted">{{if .Matching}}{{.First}}–{{.Last}} of {{.Matching}}{{else}}0 results{{end}}</span>
+        <div class="btn-group btn-group-sm">{{range .Pages}}<button type="button" class="btn {{if .Current}}btn-primary{{else}}btn-outline-primary{{end}}" hx-get="{{.URL}}">{{.Number}}</button>{{end}}</div>
+        <select id="ex19-per-page" name="per_page" class="form-select form-select-sm w-auto">
+            {{range .PageSizes}}<option value="{{.}}"{{if eq . $.Query.PerPage}} selected{{end}}>{{.}} / page</option>{{end}}
+        </select>
+    </div>
+</form>{{end}}
+
+{{define "page"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Exercise 19: Data Table</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+<div class="container my-4">
+    <p class="small"><a href="/">← Back to the tutorial</a></p>
+    {{template "table" .}}
+</div>
+</body>
+</html>{{end}}
+`))
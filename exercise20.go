@@ -0,0 +1,210 @@
+package main
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 20: Preloading
+// ----------------------------------------------------------------------------------
+// The preload extension requests a link's content on mousedown or mouseover, before
+// the click. The response is cacheable, so the real click is answered by the browser
+// cache and never reaches the server. The page marks preload requests with an
+// X-Preload header, and every fragment reports back once it is shown, which is how
+// the server can count cache hits it never sees directly.
+
+var ex20Items = []string{"Getting started", "Templates", "Middleware"}
+
+var ex20Modes = []struct {
+	Key   string
+	Title string
+	Attr  string // value of the preload attribute, "" for none
+}{
+	{Key: "none", Title: "No preload"},
+	{Key: "mousedown", Title: "preload (mousedown)", Attr: "mousedown"},
+	{Key: "mouseover", Title: "preload=\"mouseover\"", Attr: "mouseover"},
+}
+
+var ex20Latencies = []time.Duration{0, 300 * time.Millisecond, 800 * time.Millisecond, 1500 * time.Millisecond}
+
+// How many of the latest renders a visitor keeps. A cached response lives for 30s,
+// so the fragments still being swapped in are among the latest; older IDs are
+// ignored.
+const ex20RenderWindow = 32
+
+type ex20Render struct {
+	Preload bool
+	Seen    bool
+}
+
+type ex20State struct {
+	Latency    time.Duration
+	Renders    [ex20RenderWindow]ex20Render // render ID n lives in slot n % ex20RenderWindow
+	NextRender int                          // ID of the next render
+	Preloads   int
+	Hits       int // shown without a request of its own
+	Misses     int // clicks that waited for the server
+}
+
+func newEx20State() *ex20State {
+	return &ex20State{Latency: 800 * time.Millisecond}
+}
+
+var ex20Store = newSessionStore(newEx20State)
+
+type ex20ItemView struct {
+	Title      string
+	Preload    bool
+	RenderedAt string
+	Took       time.Duration
+	SeenURL    string
+}
+
+type ex20StatsView struct {
+	Preloads int
+	Hits     int
+	Misses   int
+}
+
+func addExercise20Endpoints(mux *http.ServeMux, endpoint func(string) string, clock Clock, latency Latency) {
+	mux.HandleFunc("GET /exercise20/item", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil || id < 0 || id >= len(ex20Items) {
+			http.Error(w, "unknown item", http.StatusNotFound)
+			return
+		}
+		mode := r.URL.Query().Get("mode")
+		preload := r.Header.Get("X-Preload") == "true"
+
+		var delay time.Duration
+		var render int
+		ex20Store.with(w, r, func(s *ex20State) {
+			delay = s.Latency
+			render = s.NextRender
+			s.NextRender++
+			s.Renders[render%ex20RenderWindow] = ex20Render{Preload: preload}
+			if preload {
+				s.Preloads++
+			}
+		})
+		if preload {
+			log.Printf("Exercise 20: preload of %q (%s)", ex20Items[id], mode)
+		} else {
+			log.Printf("Exercise 20: click on %q (%s)", ex20Items[id], mode)
+		}
+
+		time.Sleep(latency(delay))
+		if mode == "none" {
+			w.Header().Set("Cache-Control", "no-store")
+		} else {
+			// Lets the click reuse the preloaded response instead of asking again
+			w.Header().Set("Cache-Control", "private, max-age=30")
+		}
+		ex20Tmpl.ExecuteTemplate(w, "item", ex20ItemView{
+			Title:      ex20Items[id],
+			Preload:    preload,
+			RenderedAt: clock.Now().Format("15:04:05.000"),
+			Took:       delay,
+			SeenURL:    endpoint("/exercise20/seen?render=" + strconv.Itoa(render)),
+		})
+	}))
+
+	// Sent by a fragment when it is swapped in. A preload, or a response that was
+	// already shown once, means the click was answered from the cache.
+	mux.HandleFunc("POST /exercise20/seen", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		render, _ := strconv.Atoi(r.URL.Query().Get("render"))
+		var stats ex20StatsView
+		ex20Store.with(w, r, func(s *ex20State) {
+			// Renders that dropped out of the window are no longer counted
+			if render >= max(0, s.NextRender-ex20RenderWindow) && render < s.NextRender {
+				rd := &s.Renders[render%ex20RenderWindow]
+				if rd.Preload || rd.Seen {
+					s.Hits++
+				} else {
+					s.Misses++
+				}
+				rd.Seen = true
+			}
+			stats = ex20StatsView{Preloads: s.Preloads, Hits: s.Hits, Misses: s.Misses}
+		})
+		ex20Tmpl.ExecuteTemplate(w, "stats", stats)
+	}))
+
+	mux.HandleFunc("POST /exercise20/latency", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ms, err := strconv.Atoi(r.PostFormValue("latency"))
+		delay := time.Duration(ms) * time.Millisecond
+		if err != nil || !slices.Contains(ex20Latencies, delay) {
+			http.Error(w, "unsupported latency", http.StatusBadRequest)
+			return
+		}
+		ex20Store.with(w, r, func(s *ex20State) { s.Latency = delay })
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	mux.HandleFunc("GET /exercise20/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex20Store.reset(w, r)
+
+		type link struct {
+			Title string
+			URL   string
+		}
+		type column struct {
+			Title string
+			Attr  string
+			Links []link
+		}
+		var columns []column
+		for _, m := range ex20Modes {
+			col := column{Title: m.Title, Attr: m.Attr}
+			for id, title := range ex20Items {
+				col.Links = append(col.Links, link{title, endpoint("/exercise20/item?id=" + strconv.Itoa(id) + "&mode=" + m.Key)})
+			}
+			columns = append(columns, col)
+		}
+		var latencies []int64
+		for _, l := range ex20Latencies {
+			latencies = append(latencies, l.Milliseconds())
+		}
+		ex20Tmpl.ExecuteTemplate(w, "demo", map[string]interface{}{
+			"Columns":    columns,
+			"Latencies":  latencies,
+			"Default":    newEx20State().Latency.Milliseconds(),
+			"LatencyURL": endpoint("/exercise20/latency"),
+			"Stats":      ex20StatsView{},
+		})
+	}))
+}
+
+var ex20Tmpl = template.Must(template.New("exercise20").Parse(`
+{{define "item"}}<div class="card card-body p-2 small">
+    <strong>{{.Title}}</strong>
+    <div class="text-muted">Rendered at {{.RenderedAt}} for a {{if .Preload}}preload{{else}}click{{end}}, after {{.Took}}</div>
+    <span hx-post="{{.SeenURL}}" hx-trigger="load" hx-target="#ex20-stats" hx-swap="outerHTML"></span>
+</div>{{end}}
+
+{{define "stats"}}<div id="ex20-stats" class="d-flex flex-wrap gap-3 small">
+    <span>Preloads sent: <strong>{{.Preloads}}</strong></span>
+    <span class="text-success">Clicks answered from the cache: <strong>{{.Hits}}</strong></span>
+    <span class="text-danger">Clicks that waited for the server: <strong>{{.Misses}}</strong></span>
+</div>{{end}}
+
+{{define "demo"}}<div class="d-flex align-items-center gap-2 small mb-2">
+    <label for="ex20-latency">Server latency</label>
+    <select id="ex20-latency" name="latency" class="form-select form-select-sm w-auto" hx-post="{{.LatencyURL}}" hx-swap="none">
+        {{range .Latencies}}<option value="{{.}}"{{if eq . $.Default}} selected{{end}}>{{.}} ms</option>{{end}}
+    </select>
+</div>
+<div class="row g-2 mb-2" hx-ext="preload" hx-target="#ex20-view">
+    {{range .Columns}}<div class="col">
+        <div class="small text-muted mb-1">{{.Title}}</div>
+        <div class="list-group">{{$attr := .Attr}}{{range .Links}}<button class="list-group-item list-group-item-action small py-1" hx-get="{{.URL}}"{{if $attr}} preload="{{$attr}}"{{end}}>{{.Title}}</button>{{end}}</div>
+    </div>{{end}}
+</div>
+<div id="ex20-view" class="mb-2"><div class="text-muted small">Hover or click an item.</div></div>
+{{template "stats" .Stats}}{{end}}
+`))
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 21: Duplicate Submissions
+// ----------------------------------------------------------------------------------
+// Like exercise 5 the order takes a second to process, which is plenty of time to
+// click twice. hx-disabled-elt and hx-sync stop most duplicates in the browser, but
+// retries and other tabs still get through, so every form also carries an
+// idempotency key. The server processes each key once; a repeat waits for the first
+// request if needed and gets the same result back. Finished keys are forgotten after
+// ex21KeyTTL, long past any retry.
+
+const ex21ProcessingTime = 1 * time.Second
+
+const ex21KeyTTL = 10 * time.Minute
+
+var ex21Items = []string{"Coffee", "Tea", "Cake"}
+
+type ex21Order struct {
+	Number   int
+	Item     string
+	Quantity int
+	Key      string
+}
+
+// One entry per idempotency key; done is closed once Order and Finished are filled in
+type ex21Entry struct {
+	Order    ex21Order
+	Finished time.Time
+	done     chan struct{}
+}
+
+type ex21State struct {
+	Entries      map[string]*ex21Entry
+	NextNumber   int
+	Accepted     int
+	Deduplicated int
+}
+
+func newEx21State() *ex21State {
+	return &ex21State{Entries: map[string]*ex21Entry{}, NextNumber: 1001}
+}
+
+// expire forgets the keys that finished more than ex21KeyTTL before now. Keys still
+// being processed stay, since duplicates may be waiting on them.
+func (s *ex21State) expire(now time.Time) {
+	for key, entry := range s.Entries {
+		if !entry.Finished.IsZero() && now.Sub(entry.Finished) > ex21KeyTTL {
+			delete(s.Entries, key)
+		}
+	}
+}
+
+var ex21Store = newSessionStore(newEx21State)
+
+func ex21NewKey() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("exercise 21: reading random bytes: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+type ex21FormView struct {
+	Title     string
+	Protected bool
+	Key       string
+	Items     []string
+	OrderURL  string
+}
+
+type ex21CountsView struct {
+	Accepted     int
+	Deduplicated int
+	OOB          bool
+}
+
+type ex21ResultView struct {
+	ex21Order
+	Form      string
+	Duplicate bool
+	Counts    ex21CountsView
+	OrderURL  string
+}
+
+func addExercise21Endpoints(mux *http.ServeMux, endpoint func(string) string, clock Clock, latency Latency) {
+	newForms := func() []ex21FormView {
+		return []ex21FormView{
+			{Title: "Unprotected", Key: ex21NewKey(), Items: ex21Items, OrderURL: endpoint("/exercise21/order")},
+			{Title: "hx-disabled-elt + hx-sync", Protected: true, Key: ex21NewKey(), Items: ex21Items, OrderURL: endpoint("/exercise21/order")},
+		}
+	}
+
+	mux.HandleFunc("POST /exercise21/order", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		key := r.PostFormValue("idempotency_key")
+		item := r.PostFormValue("item")
+		quantity, err := strconv.Atoi(r.PostFormValue("quantity"))
+		if key == "" || !slices.Contains(ex21Items, item) || err != nil || quantity < 1 || quantity > 5 {
+			writeErrorFragment(w, http.StatusBadRequest, "An order needs an idempotency key, an item and a quantity from 1 to 5.")
+			return
+		}
+
+		// Claim the key, or find out it was claimed before
+		var entry *ex21Entry
+		duplicate := false
+		ex21Store.with(w, r, func(s *ex21State) {
+			s.expire(clock.Now())
+			if entry, duplicate = s.Entries[key]; duplicate {
+				s.Deduplicated++
+				return
+			}
+			entry = &ex21Entry{done: make(chan struct{})}
+			s.Entries[key] = entry
+		})
+
+		if duplicate {
+			// The first request may still be running; its result is the answer
+			select {
+			case <-entry.done:
+			case <-r.Context().Done():
+				return
+			}
+		} else {
+			time.Sleep(latency(ex21ProcessingTime))
+			ex21Store.with(w, r, func(s *ex21State) {
+				entry.Order = ex21Order{Number: s.NextNumber, Item: item, Quantity: quantity, Key: key}
+				entry.Finished = clock.Now()
+				s.NextNumber++
+				s.Accepted++
+			})
+			close(entry.done)
+		}
+
+		result := ex21ResultView{
+			ex21Order: entry.Order,
+			Form:      r.PostFormValue("form"),
+			Duplicate: duplicate,
+			OrderURL:  endpoint("/exercise21/order"),
+		}
+		ex21Store.with(w, r, func(s *ex21State) {
+			result.Counts = ex21CountsView{Accepted: s.Accepted, Deduplicated: s.Deduplicated, OOB: true}
+		})
+		ex21Tmpl.ExecuteTemplate(w, "result", result)
+	}))
+
+	// New keys mean new orders; the counts and earlier keys stay
+	mux.HandleFunc("GET /exercise21/forms", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex21Tmpl.ExecuteTemplate(w, "forms", newForms())
+	}))
+
+	mux.HandleFunc("GET /exercise21/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex21Store.reset(w, r)
+		ex21Tmpl.ExecuteTemplate(w, "demo", map[string]interface{}{
+			"Forms":    newForms(),
+			"FormsURL": endpoint("/exercise21/forms"),
+			"Counts":   ex21CountsView{},
+		})
+	}))
+}
+
+var ex21Tmpl = template.Must(template.New("exercise21").Parse(`
+{{define "form"}}<form class="border rounded p-2" hx-post="{{.OrderURL}}" hx-target="#ex21-result"
+      {{- if .Protected}} hx-disabled-elt="find button" hx-sync="this:drop"{{end}}>
+    <div class="small fw-semibold mb-1">{{.Title}}</div>
+    <input type="hidden" name="form" value="{{.Title}}">
+    <input type="hidden" name="idempotency_key" value="{{.Key}}">
+    <div class="d-flex gap-1 mb-2">
+        <select name="item" class="form-select form-select-sm">{{range .Items}}<option>{{.}}</option>{{end}}</select>
+        <input type="number" name="quantity" value="1" min="1" max="5" class="form-control form-control-sm" style="width: 4.5rem">
+    </div>
+    <button type="submit" class="btn btn-sm btn-success">Place order <span class="spinner-border spinner-border-sm htmx-indicator"></span></button>
+    <div class="text-muted small mt-1">Key <code>{{.Key}}</code></div>
+</form>{{end}}
+
+{{define "forms"}}<div id="ex21-forms" class="row g-2 mb-2">
+    {{range .}}<div class="col">{{template "form" .}}</div>{{end}}
+</div>{{end}}
+
+{{define "counts"}}<div id="ex21-counts" class="small"{{if .OOB}} hx-swap-oob="true"{{end}}>
+    Accepted: <strong>{{.Accepted}}</strong> · Deduplicated: <strong>{{.Deduplicated}}</strong>
+</div>{{end}}
+
+{{define "result"}}<div class="alert {{if .Duplicate}}alert-warning{{else}}alert-success{{end}} small py-2 mb-0">
+    <strong>Order #{{.Number}}</strong>: {{.Quantity}} × {{.Item}}
+    {{if .Duplicate}}<div>Duplicate from "{{.Form}}": key <code>{{.Key}}</code> was already used, so this is the original result.</div>
+    {{else}}<div>Accepted from "{{.Form}}" with key <code>{{.Key}}</code>.</div>{{end}}
+    <button class="btn btn-sm btn-outline-secondary mt-1" hx-post="{{.OrderURL}}" hx-target="#ex21-result"
+            hx-vals='{"form": "retry", "idempotency_key": "{{.Key}}", "item": "{{.Item}}", "quantity": "{{.Quantity}}"}'>Retry with the same key</button>
+</div>
+{{template "counts" .Counts}}{{end}}
+
+{{define "demo"}}{{template "forms" .Forms}}
+<button class="btn btn-sm btn-outline-primary mb-2" hx-get="{{.FormsURL}}" hx-target="#ex21-forms" hx-swap="outerHTML">New order (fresh keys)</button>
+<div id="ex21-result" class="mb-2"></div>
+{{template "counts" .Counts}}{{end}}
+`))
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 7: Server-Triggered Events
+// ----------------------------------------------------------------------------------
+// Saving an item sends three HX-Trigger* headers with JSON payloads.
+// The list and the counter listen for those events and refresh themselves.
+
+type ex7Item struct {
+	ID   int
+	Name string
+}
+
+type ex7State struct {
+	NextID int
+	Items  []ex7Item
+}
+
+var ex7Store = newSessionStore(func() *ex7State { return &ex7State{} })
+
+// Event payloads. The JSON field names are what the browser sees in event.detail.
+type ex7ItemSaved struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ex7CountChanged struct {
+	Count int `json:"count"`
+}
+
+type ex7Message struct {
+	Level string `json:"level"`
+	Text  string `json:"text"`
+}
+
+// One struct per header: each field is an event name mapped to its payload
+type ex7Trigger struct {
+	ItemSaved ex7ItemSaved `json:"itemSaved"`
+}
+
+type ex7TriggerAfterSwap struct {
+	ItemCountChanged ex7CountChanged `json:"itemCountChanged"`
+}
+
+type ex7TriggerAfterSettle struct {
+	ShowMessage ex7Message `json:"showMessage"`
+}
+
+type ex7View struct {
+	Items    []ex7Item
+	Error    string
+	ItemsURL string
+	CountURL string
+}
+
+func addExercise7Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	newView := func(s *ex7State) ex7View {
+		return ex7View{
+			Items:    append([]ex7Item(nil), s.Items...),
+			ItemsURL: endpoint("/exercise7/items"),
+			CountURL: endpoint("/exercise7/count"),
+		}
+	}
+
+	mux.HandleFunc("GET /exercise7/items", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		var view ex7View
+		ex7Store.with(w, r, func(s *ex7State) { view = newView(s) })
+		ex7Tmpl.ExecuteTemplate(w, "list", view)
+	}))
+	// Saves a new item and fires the events
+	mux.HandleFunc("POST /exercise7/items", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSpace(r.PostFormValue("name"))
+		var view ex7View
+		var saved ex7Item
+		ex7Store.with(w, r, func(s *ex7State) {
+			if name != "" {
+				s.NextID++
+				saved = ex7Item{ID: s.NextID, Name: name}
+				s.Items = append(s.Items, saved)
+			}
+			view = newView(s)
+		})
+
+		if name == "" {
+			// No headers: nothing changed, so nobody needs to refresh
+			view.Error = "Please enter a name."
+			ex7Tmpl.ExecuteTemplate(w, "form", view)
+			return
+		}
+
+		setTriggerHeader(w, hxTrigger, ex7Trigger{
+			ItemSaved: ex7ItemSaved{ID: saved.ID, Name: saved.Name},
+		})
+		setTriggerHeader(w, hxTriggerAfterSwap, ex7TriggerAfterSwap{
+			ItemCountChanged: ex7CountChanged{Count: len(view.Items)},
+		})
+		setTriggerHeader(w, hxTriggerAfterSettle, ex7TriggerAfterSettle{
+			ShowMessage: ex7Message{Level: "success", Text: "Saved \"" + saved.Name + "\""},
+		})
+		ex7Tmpl.ExecuteTemplate(w, "form", view)
+	}))
+	mux.HandleFunc("GET /exercise7/count", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		var view ex7View
+		ex7Store.with(w, r, func(s *ex7State) { view = newView(s) })
+		ex7Tmpl.ExecuteTemplate(w, "count", view)
+	}))
+	mux.HandleFunc("GET /exercise7/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex7Store.reset(w, r)
+		ex7Tmpl.ExecuteTemplate(w, "demo", newView(&ex7State{}))
+	}))
+}
+
+var ex7Tmpl = template.Must(template.New("exercise7").Parse(`
+{{define "form"}}<form id="ex7-form" class="mb-3" hx-post="{{.ItemsURL}}" hx-swap="outerHTML">
+    <div class="input-group">
+        <input type="text" name="name" class="form-control" placeholder="Item name">
+        <button type="submit" class="btn btn-primary">Save Item</button>
+    </div>
+    {{if .Error}}<div class="text-danger small mt-1">{{.Error}}</div>{{end}}
+</form>{{end}}
+
+{{define "count"}}<span id="ex7-count" class="badge bg-secondary" hx-get="{{.CountURL}}" hx-trigger="itemCountChanged from:body" hx-swap="outerHTML">{{len .Items}}</span>{{end}}
+
+{{define "list"}}<ul id="ex7-list" class="list-group mb-3" hx-get="{{.ItemsURL}}" hx-trigger="itemSaved from:body" hx-swap="outerHTML">
+    {{range .Items}}<li class="list-group-item">#{{.ID}} {{.Name}}</li>{{else}}<li class="list-group-item text-muted">No items yet</li>{{end}}
+</ul>{{end}}
+
+{{define "demo"}}{{template "form" .}}
+<p class="mb-2">Items saved: {{template "count" .}}</p>
+{{template "list" .}}
+<div id="ex7-message"></div>
+<pre id="ex7-events" class="small bg-light border rounded p-2 mb-0"></pre>{{end}}
+`))
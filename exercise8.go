@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 8: Sortable List
+// ----------------------------------------------------------------------------------
+// Dropping an item posts the new order. The list carries a version number, so the
+// server can spot an order that was built from an outdated list and refuse it.
+
+type ex8Item struct {
+	ID   int
+	Name string
+}
+
+type ex8State struct {
+	Version int
+	Items   []ex8Item
+}
+
+func newEx8State() *ex8State {
+	return &ex8State{
+		Version: 1,
+		Items: []ex8Item{
+			{ID: 1, Name: "Learn Go basics"},
+			{ID: 2, Name: "Write an HTTP handler"},
+			{ID: 3, Name: "Add HTMX to a page"},
+			{ID: 4, Name: "Swap fragments from the server"},
+			{ID: 5, Name: "Ship it 🚀"},
+		},
+	}
+}
+
+var ex8Store = newSessionStore(newEx8State)
+
+type ex8View struct {
+	Version    int
+	Items      []ex8Item
+	Message    string
+	Level      string
+	OrderURL   string
+	ShuffleURL string
+}
+
+// ex8Reorder returns items rearranged to match order, or false when
+// order is not a permutation of the item IDs.
+func ex8Reorder(items []ex8Item, order []int) ([]ex8Item, bool) {
+	if len(order) != len(items) {
+		return nil, false
+	}
+	byID := make(map[int]ex8Item, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	reordered := make([]ex8Item, 0, len(order))
+	for _, id := range order {
+		item, ok := byID[id]
+		if !ok {
+			return nil, false // unknown or duplicated ID
+		}
+		delete(byID, id)
+		reordered = append(reordered, item)
+	}
+	return reordered, true
+}
+
+func addExercise8Endpoints(mux *http.ServeMux, endpoint func(string) string) {
+	newView := func(s *ex8State) ex8View {
+		return ex8View{
+			Version:    s.Version,
+			Items:      append([]ex8Item(nil), s.Items...),
+			OrderURL:   endpoint("/exercise8/order"),
+			ShuffleURL: endpoint("/exercise8/shuffle"),
+		}
+	}
+
+	mux.HandleFunc("POST /exercise8/order", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		version, versionErr := strconv.Atoi(r.PostForm.Get("version"))
+		var order []int
+		for _, v := range r.PostForm["item"] {
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				order = nil
+				break
+			}
+			order = append(order, id)
+		}
+
+		status := http.StatusOK
+		var view ex8View
+		ex8Store.with(w, r, func(s *ex8State) {
+			reordered, ok := ex8Reorder(s.Items, order)
+			switch {
+			case versionErr != nil || !ok:
+				status = http.StatusBadRequest
+				view = newView(s)
+				view.Message, view.Level = "That order doesn't match the list. Here is the current one.", "danger"
+			case version != s.Version:
+				// Someone else saved first: send back what they saved
+				status = http.StatusConflict
+				view = newView(s)
+				view.Message, view.Level = "The list changed since you loaded it. Here is the latest order — try again.", "warning"
+			default:
+				s.Items = reordered
+				s.Version++
+				view = newView(s)
+				view.Message, view.Level = "Order saved (version "+strconv.Itoa(s.Version)+").", "success"
+			}
+		})
+
+		w.WriteHeader(status)
+		ex8Tmpl.ExecuteTemplate(w, "list", view)
+	}))
+
+	// Stands in for a second tab: changes the saved order behind the page's back
+	mux.HandleFunc("POST /exercise8/shuffle", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex8Store.with(w, r, func(s *ex8State) {
+			items := s.Items
+			items[0], items[len(items)-1] = items[len(items)-1], items[0]
+			s.Version++
+		})
+		fmt.Fprint(w, `<span class="text-muted small">Another tab moved the first and last items. Now drag something.</span>`)
+	}))
+
+	mux.HandleFunc("GET /exercise8/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		ex8Store.reset(w, r)
+		ex8Tmpl.ExecuteTemplate(w, "demo", newView(newEx8State()))
+	}))
+}
+
+var ex8Tmpl = template.Must(template.New("exercise8").Parse(`
+{{define "list"}}<form id="ex8-list" hx-post="{{.OrderURL}}" hx-trigger="end" hx-swap="outerHTML">
+    <input type="hidden" name="version" value="{{.Version}}">
+    {{if .Message}}<div class="alert alert-{{.Level}} py-1 small">{{.Message}}</div>{{end}}
+    <div class="list-group sortable">
+        {{range .Items}}<div class="list-group-item"><input type="hidden" name="item" value="{{.ID}}">☰ {{.Name}}</div>{{end}}
+    </div>
+</form>{{end}}
+
+{{define "demo"}}{{template "list" .}}
+<div class="mt-3 d-flex align-items-center gap-2">
+    <button class="btn btn-sm btn-outline-warning" hx-post="{{.ShuffleURL}}" hx-target="#ex8-note">Simulate another tab</button>
+    <div id="ex8-note"></div>
+</div>{{end}}
+`))
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Exercise 9: Lazy Loading
+// ----------------------------------------------------------------------------------
+// Each placeholder card fetches its own content on "load" or "intersect once".
+// The server makes every card take a different amount of time, and one of them
+// fails on its first attempt so the error fallback can be seen.
+
+type ex9Widget struct {
+	Key     string
+	Title   string
+	Trigger string // the hx-trigger used by the placeholder
+	Latency time.Duration
+	Body    string
+	// FailFirst makes the first attempt answer 500; the retry succeeds
+	FailFirst bool
+}
+
+var ex9Widgets = []ex9Widget{
+	{Key: "weather", Title: "Weather", Trigger: "load", Latency: 300 * time.Millisecond, Body: "☀️ 24°C and sunny."},
+	{Key: "stocks", Title: "Stocks", Trigger: "load", Latency: 1500 * time.Millisecond, Body: "📈 GOPHER +4.2%"},
+	{Key: "news", Title: "News", Trigger: "intersect once", Latency: 800 * time.Millisecond, Body: "📰 HTMX keeps things simple."},
+	{Key: "recommendations", Title: "Recommendations", Trigger: "intersect once", Latency: 1 * time.Second, Body: "👍 Try exercise 10 next.", FailFirst: true},
+}
+
+type ex9View struct {
+	Widget   ex9Widget
+	URL      string
+	Status   int
+	RetryURL string
+}
+
+func addExercise9Endpoints(mux *http.ServeMux, endpoint func(string) string, latency Latency) {
+	widgetURL := func(key string) string {
+		return endpoint("/exercise9/widget?card=" + url.QueryEscape(key))
+	}
+
+	mux.HandleFunc("GET /exercise9/widget", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("card")
+		attempt, _ := strconv.Atoi(r.URL.Query().Get("attempt"))
+		if attempt < 1 {
+			attempt = 1
+		}
+
+		var widget ex9Widget
+		found := false
+		for _, wd := range ex9Widgets {
+			if wd.Key == key {
+				widget, found = wd, true
+				break
+			}
+		}
+
+		view := ex9View{
+			Widget:   widget,
+			RetryURL: widgetURL(key) + "&attempt=" + strconv.Itoa(attempt+1),
+		}
+		if !found {
+			view.Widget.Title = key
+			view.Status = http.StatusNotFound
+			w.WriteHeader(view.Status)
+			ex9Tmpl.ExecuteTemplate(w, "error", view)
+			return
+		}
+
+		// Simulate a slow backend, like /exercise5/submit does
+		time.Sleep(latency(widget.Latency))
+
+		if widget.FailFirst && attempt == 1 {
+			view.Status = http.StatusInternalServerError
+			w.WriteHeader(view.Status)
+			ex9Tmpl.ExecuteTemplate(w, "error", view)
+			return
+		}
+		ex9Tmpl.ExecuteTemplate(w, "card", view)
+	}))
+
+	mux.HandleFunc("GET /exercise9/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		var views []ex9View
+		for _, wd := range ex9Widgets {
+			views = append(views, ex9View{Widget: wd, URL: widgetURL(wd.Key)})
+		}
+		ex9Tmpl.ExecuteTemplate(w, "demo", views)
+	}))
+}
+
+var ex9Tmpl = template.Must(template.New("exercise9").Parse(`
+{{define "placeholder"}}<div class="card mb-2 ex9-card" hx-get="{{.URL}}" hx-trigger="{{.Widget.Trigger}}" hx-swap="outerHTML">
+    <div class="card-body small text-muted"><span class="spinner-border spinner-border-sm"></span> {{.Widget.Title}} loads on <code>{{.Widget.Trigger}}</code>...</div>
+</div>{{end}}
+
+{{define "card"}}<div class="card mb-2 ex9-card ex9-fade">
+    <div class="card-body small"><strong>{{.Widget.Title}}</strong> <span class="badge bg-light text-dark">{{.Widget.Latency}}</span><div>{{.Widget.Body}}</div></div>
+</div>{{end}}
+
+{{define "error"}}<div class="card mb-2 ex9-card ex9-fade border-danger">
+    <div class="card-body small text-danger">
+        Could not load {{.Widget.Title}} ({{.Status}}).
+        <button class="btn btn-sm btn-outline-danger ms-2" hx-get="{{.RetryURL}}" hx-target="closest .ex9-card" hx-swap="outerHTML">Retry</button>
+    </div>
+</div>{{end}}
+
+{{define "demo"}}<div class="ex9-scroll">
+    {{range .}}{{if eq .Widget.Trigger "load"}}{{template "placeholder" .}}{{end}}{{end}}
+    <p class="small text-muted my-4">Scroll down ↓</p>
+    <div class="ex9-spacer"></div>
+    {{range .}}{{if ne .Widget.Trigger "load"}}{{template "placeholder" .}}{{end}}{{end}}
+</div>{{end}}
+`))
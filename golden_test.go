@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden with the current output")
+
+// Idempotency keys in exercise 21 are random on every render
+var volatileKey = regexp.MustCompile(`\b[0-9a-f]{16}\b`)
+
+func normalize(body []byte) []byte {
+	return volatileKey.ReplaceAll(body, []byte("KEY"))
+}
+
+// assertGolden compares got with testdata/<name>.golden, or rewrites the file
+// when the test runs with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test -update to accept it)\n%s", path, firstDifference(want, got))
+	}
+}
+
+// firstDifference describes the first line where want and got disagree.
+func firstDifference(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+// goldenName turns a request into a file name, e.g. "put_exercise6_contact_1".
+func goldenName(method, path string) string {
+	name := strings.ToLower(method) + "_" + strings.Trim(path, "/")
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func TestGoldenFragments(t *testing.T) {
+	srv := newTestServer(t)
+
+	type request struct {
+		method string
+		path   string
+		form   url.Values
+	}
+	requests := []request{
+		{http.MethodPost, "/exercise1", nil},
+		{http.MethodGet, "/exercise2", nil},
+		{http.MethodGet, "/exercise3", nil},
+		{http.MethodGet, "/exercise4?user-input=gopher", nil},
+		{http.MethodPost, "/exercise5/submit", url.Values{"name": {"Jane"}}},
+		{http.MethodGet, "/exercise6/contact/1", nil},
+		{http.MethodPut, "/exercise6/contact/1", url.Values{"name": {"Ann Gopher"}, "email": {"ann@example.com"}}},
+	}
+	for n := 1; n <= 21; n++ {
+		requests = append(requests,
+			request{http.MethodGet, fmt.Sprintf("/exercise%d/reset", n), nil},
+			request{http.MethodGet, fmt.Sprintf("/code/exercise%d", n), nil},
+		)
+	}
+	// Exercises 7+ serve their own source file as the Go listing, which needs no snapshot
+	for n := 1; n <= 6; n++ {
+		requests = append(requests, request{http.MethodGet, fmt.Sprintf("/code/exercise%d/go", n), nil})
+	}
+
+	for _, req := range requests {
+		name := goldenName(req.method, req.path)
+		t.Run(name, func(t *testing.T) {
+			resp, got := fetch(t, srv, req.method, req.path, req.form)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			assertGolden(t, name, normalize(got))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Response headers that make HTMX fire client-side events.
+// The value is either a plain event name or a JSON object of event name -> detail.
+const (
+	hxTrigger            = "HX-Trigger"
+	hxTriggerAfterSwap   = "HX-Trigger-After-Swap"
+	hxTriggerAfterSettle = "HX-Trigger-After-Settle"
+)
+
+// setTriggerHeader encodes events as JSON and stores it in one of the HX-Trigger
+// headers. Each exported field of the events struct becomes one event, with the
+// field value as its detail.
+func setTriggerHeader(w http.ResponseWriter, header string, events any) {
+	b, err := json.Marshal(events)
+	if err != nil {
+		log.Printf("Could not encode %s header: %s", header, err)
+		return
+	}
+	w.Header().Set(header, asciiJSON(string(b)))
+}
+
+// asciiJSON escapes non-ASCII characters as \uXXXX so the JSON survives
+// being sent as a header value (browsers decode headers as Latin-1).
+func asciiJSON(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r < 0x80:
+			sb.WriteRune(r)
+		case r > 0xFFFF:
+			// Characters outside the BMP need a UTF-16 surrogate pair
+			r -= 0x10000
+			fmt.Fprintf(&sb, `\u%04x\u%04x`, 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+		default:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		}
+	}
+	return sb.String()
+}
+
+var errorFragmentTmpl = template.Must(template.New("error-fragment").Parse(
+	`<div class="alert {{if ge .Status 500}}alert-danger{{else}}alert-warning{{end}} py-2 small mb-0" role="alert">` +
+		`<strong>{{.Status}} {{.StatusText}}</strong><div>{{.Message}}</div></div>`))
+
+// writeErrorFragment answers with an error status and a small alert describing it.
+// HTMX swaps it in wherever the page routes error responses (e.g. hx-target-4*).
+func writeErrorFragment(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	errorFragmentTmpl.Execute(w, struct {
+		Status     int
+		StatusText string
+		Message    string
+	}{status, http.StatusText(status), message})
+}
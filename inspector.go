@@ -0,0 +1,187 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ----------------------------------------------------------------------------------
+// Request Inspector
+// ----------------------------------------------------------------------------------
+// A network tab for htmx: every call a demo makes is recorded per visitor, with the
+// HX-* headers htmx sent, the parameters, and the status and HX-* headers that came
+// back. Each demo pane polls /inspector?exercise=N while its panel is open.
+
+// How many requests each visitor's inspector keeps per exercise
+const inspectorSize = 20
+
+type inspectorPair struct {
+	Name  string
+	Value string
+}
+
+type inspectorSection struct {
+	Title string
+	Pairs []inspectorPair
+}
+
+type inspectedRequest struct {
+	Seq      int
+	Exercise string
+	At       string
+	Method   string
+	URL      string
+	Status   int
+	Sections []inspectorSection
+}
+
+// inspectorRing is a ring buffer of the most recent requests of one exercise
+type inspectorRing struct {
+	entries [inspectorSize]inspectedRequest
+	next    int // slot for the next entry
+	count   int
+}
+
+func (r *inspectorRing) add(e inspectedRequest) {
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % inspectorSize
+	r.count = min(r.count+1, inspectorSize)
+}
+
+// newest lists the kept requests, newest first.
+func (r *inspectorRing) newest() []inspectedRequest {
+	var out []inspectedRequest
+	for i := 1; i <= r.count; i++ {
+		out = append(out, r.entries[(r.next-i+inspectorSize)%inspectorSize])
+	}
+	return out
+}
+
+// inspectorLog keeps one ring per exercise, so the demos that poll (exercises 3
+// and 14) cannot push the requests of the other exercises out.
+type inspectorLog struct {
+	rings map[string]*inspectorRing
+	seq   int
+}
+
+func (l *inspectorLog) add(e inspectedRequest) {
+	l.seq++
+	e.Seq = l.seq
+	ring := l.rings[e.Exercise]
+	if ring == nil {
+		ring = &inspectorRing{}
+		l.rings[e.Exercise] = ring
+	}
+	ring.add(e)
+}
+
+// recent lists the kept requests for one exercise, newest first. For "" it lists
+// the newest inspectorSize requests of all exercises.
+func (l *inspectorLog) recent(exercise string) []inspectedRequest {
+	if exercise != "" {
+		if ring := l.rings[exercise]; ring != nil {
+			return ring.newest()
+		}
+		return nil
+	}
+	var out []inspectedRequest
+	for _, ring := range l.rings {
+		out = append(out, ring.newest()...)
+	}
+	slices.SortFunc(out, func(a, b inspectedRequest) int { return b.Seq - a.Seq })
+	return out[:min(len(out), inspectorSize)]
+}
+
+var inspectorStore = newSessionStore(func() *inspectorLog { return &inspectorLog{rings: map[string]*inspectorRing{}} })
+
+// recordRequests adds the demo and reset calls of every exercise to the caller's
+// inspector. Password and secret fields are masked. The route is looked up in mux
+// before next runs, so every other request, like /metrics or a 404, passes through
+// without a session cookie and with its body unread.
+func recordRequests(clock Clock, mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		exercise, route := classifyRoute(pattern)
+		if route != "demo" && route != "reset" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The session cookie has to be set before the handler writes anything
+		sessionID(w, r)
+		r.ParseForm()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		entry := inspectedRequest{
+			Exercise: exercise,
+			At:       clock.Now().Format("15:04:05.000"),
+			Method:   r.Method,
+			URL:      r.URL.RequestURI(),
+			Status:   rec.status,
+			Sections: []inspectorSection{
+				{"Request headers", hxHeaders(r.Header)},
+				{"Parameters", inspectorValues(r.Form)},
+				{"Response headers", hxHeaders(rec.Header())},
+			},
+		}
+		inspectorStore.with(w, r, func(l *inspectorLog) { l.add(entry) })
+	})
+}
+
+// hxHeaders picks the headers htmx reads or writes, in their usual spelling.
+func hxHeaders(h http.Header) []inspectorPair {
+	var pairs []inspectorPair
+	for name, values := range h {
+		if strings.HasPrefix(name, "Hx-") {
+			pairs = append(pairs, inspectorPair{"HX-" + name[len("Hx-"):], strings.Join(values, ", ")})
+		} else if name == "Content-Type" {
+			pairs = append(pairs, inspectorPair{name, strings.Join(values, ", ")})
+		}
+	}
+	slices.SortFunc(pairs, func(a, b inspectorPair) int { return strings.Compare(a.Name, b.Name) })
+	return pairs
+}
+
+func inspectorValues(form url.Values) []inspectorPair {
+	var pairs []inspectorPair
+	for name, values := range form {
+		value := strings.Join(values, ", ")
+		if lower := strings.ToLower(name); strings.Contains(lower, "password") || strings.Contains(lower, "secret") {
+			value = "••••••"
+		}
+		pairs = append(pairs, inspectorPair{name, value})
+	}
+	slices.SortFunc(pairs, func(a, b inspectorPair) int { return strings.Compare(a.Name, b.Name) })
+	return pairs
+}
+
+func addInspectorEndpoints(mux *http.ServeMux) {
+	mux.HandleFunc("GET /inspector", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		exercise := r.URL.Query().Get("exercise")
+		if _, err := strconv.Atoi(exercise); exercise != "" && err != nil {
+			http.Error(w, "exercise must be a number", http.StatusBadRequest)
+			return
+		}
+		var entries []inspectedRequest
+		inspectorStore.with(w, r, func(l *inspectorLog) { entries = l.recent(exercise) })
+		w.Header().Set("Cache-Control", "no-store")
+		inspectorTmpl.ExecuteTemplate(w, "inspector", entries)
+	}))
+}
+
+var inspectorTmpl = template.Must(template.New("inspector").Parse(`
+{{define "inspector"}}{{range .}}<details class="border-bottom py-1">
+    <summary><code>#{{.Seq}}</code> <span class="text-muted">{{.At}}</span> <strong>{{.Method}}</strong> {{.URL}}
+        <span class="badge {{if ge .Status 400}}bg-danger{{else}}bg-success{{end}}">{{.Status}}</span></summary>
+    {{range .Sections}}<div class="text-muted mt-1">{{.Title}}</div>
+    {{range .Pairs}}<div class="ms-2"><code>{{.Name}}</code>: {{.Value}}</div>{{else}}<div class="ms-2 text-muted">none</div>{{end}}
+    {{end}}
+</details>{{else}}<div class="text-muted">No requests yet. Use the demo above.</div>{{end}}{{end}}
+`))
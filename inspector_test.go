@@ -0,0 +1,138 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestInspectorLogKeepsTheNewest(t *testing.T) {
+	l := inspectorLog{rings: map[string]*inspectorRing{}}
+	for i := 0; i < inspectorSize+5; i++ {
+		l.add(inspectedRequest{Exercise: "1"})
+	}
+	got := l.recent("")
+	if len(got) != inspectorSize {
+		t.Fatalf("kept %d requests, want %d", len(got), inspectorSize)
+	}
+	if got[0].Seq != inspectorSize+5 || got[len(got)-1].Seq != 6 {
+		t.Errorf("kept #%d to #%d, want #%d to #6", got[0].Seq, got[len(got)-1].Seq, inspectorSize+5)
+	}
+	if len(l.recent("2")) != 0 {
+		t.Error("recent(\"2\") found requests of exercise 1")
+	}
+}
+
+func TestInspector(t *testing.T) {
+	mux := newMux(func(path string) string { return path }, fakeClock{testTime}, noLatency)
+	srv := httptest.NewServer(recordRequests(fakeClock{testTime}, mux, mux))
+	t.Cleanup(srv.Close)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Client().Jar = jar
+
+	fetch(t, srv, http.MethodPost, "/exercise7/items", url.Values{"name": {"Milk"}})
+	fetch(t, srv, http.MethodPost, "/exercise10/validate/password", url.Values{"password": {"hunter2hunter2"}})
+	fetch(t, srv, http.MethodGet, "/code/exercise7", nil)
+
+	_, got := fetch(t, srv, http.MethodGet, "/inspector?exercise=7", nil)
+	for _, want := range []string{
+		"#1", "15:04:05.000", "POST", "/exercise7/items", ">200<",
+		"<code>HX-Request</code>: true",
+		"<code>name</code>: Milk",
+		"<code>HX-Trigger</code>:", // set by the handler
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("exercise 7 inspector is missing %s\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"/exercise10/", "/code/exercise7"} {
+		if strings.Contains(string(got), unwanted) {
+			t.Errorf("exercise 7 inspector shows %s", unwanted)
+		}
+	}
+
+	_, got = fetch(t, srv, http.MethodGet, "/inspector?exercise=10", nil)
+	if !strings.Contains(string(got), "<code>password</code>: ••••••") || strings.Contains(string(got), "hunter2") {
+		t.Errorf("exercise 10 inspector should mask the password\n%s", got)
+	}
+
+	// Without the cookie jar the request comes from a new visitor
+	resp, err := http.Get(srv.URL + "/inspector")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "No requests yet") {
+		t.Errorf("a new visitor's inspector is not empty\n%s", body)
+	}
+}
+
+// The polling demos keep the requests of the other exercises in the inspector
+func TestInspectorKeepsOtherExercisesWhilePolling(t *testing.T) {
+	mux := newMux(func(path string) string { return path }, fakeClock{testTime}, noLatency)
+	srv := httptest.NewServer(recordRequests(fakeClock{testTime}, mux, mux))
+	t.Cleanup(srv.Close)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Client().Jar = jar
+
+	fetch(t, srv, http.MethodPost, "/exercise7/items", url.Values{"name": {"Milk"}})
+	for range 2 * inspectorSize {
+		fetch(t, srv, http.MethodGet, "/exercise3", nil)
+		fetch(t, srv, http.MethodGet, "/exercise14/like?post=1", nil)
+	}
+
+	if _, got := fetch(t, srv, http.MethodGet, "/inspector?exercise=7", nil); !strings.Contains(string(got), "/exercise7/items") {
+		t.Errorf("exercise 7 inspector lost its request to the polls\n%s", got)
+	}
+	if _, got := fetch(t, srv, http.MethodGet, "/inspector?exercise=3", nil); strings.Count(string(got), "<details") != inspectorSize {
+		t.Errorf("exercise 3 inspector should keep %d requests\n%s", inspectorSize, got)
+	}
+}
+
+// Requests outside the exercises get no session and keep their body
+func TestInspectorSkipsOtherRoutes(t *testing.T) {
+	mux := newMux(func(path string) string { return path }, fakeClock{testTime}, noLatency)
+	mux.Handle("GET /metrics", newMetrics().handler())
+	addHealthEndpoints(mux, &readiness{})
+	var bodyLeft bool
+	mux.HandleFunc("POST /echo", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodyLeft = string(b) == "a=1"
+	})
+	handler := recordRequests(fakeClock{testTime}, mux, withNotFound(mux))
+
+	for _, path := range []string{"/metrics", "/healthz", "/readyz", "/", "/nowhere", "/code/exercise1"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if cookie := rec.Header().Get("Set-Cookie"); cookie != "" {
+			t.Errorf("GET %s set a cookie: %s", path, cookie)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("a=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if !bodyLeft {
+		t.Error("the body of POST /echo was read before its handler")
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exercise1/reset", nil))
+	if rec.Header().Get("Set-Cookie") == "" {
+		t.Error("GET /exercise1/reset got no session cookie")
+	}
+}
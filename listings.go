@@ -0,0 +1,1039 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"net/http"
+)
+
+// The Go tab of the newer exercises is served straight from their source files,
+// so the listing can never drift from the code the demo actually runs.
+//
+//go:embed exercise*.go
+var exerciseSources embed.FS
+
+// Helper that serves one exercise source file as plain text
+func serveSource(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		src, err := exerciseSources.ReadFile(name)
+		if err != nil {
+			http.Error(w, "listing not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(src)
+	}
+}
+
+func addExercise7CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise7", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 7: Server-Triggered Events</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 7: Server-Triggered Events</h1>
+        <p>Saving an item makes the server send HX-Trigger headers. Other elements listen for those events and refresh themselves.</p>
+
+        <div id="ex7-demo">
+            <!-- The form swaps itself; the response headers fire the events -->
+            <form id="ex7-form" class="mb-3"
+                  hx-post="%s/exercise7/items"
+                  hx-swap="outerHTML">
+                <div class="input-group">
+                    <input type="text" name="name" class="form-control" placeholder="Item name">
+                    <button type="submit" class="btn btn-primary">Save Item</button>
+                </div>
+            </form>
+
+            <!-- Refreshes after the swap (HX-Trigger-After-Swap) -->
+            <p>Items saved:
+                <span id="ex7-count" class="badge bg-secondary"
+                      hx-get="%s/exercise7/count"
+                      hx-trigger="itemCountChanged from:body"
+                      hx-swap="outerHTML">0</span>
+            </p>
+
+            <!-- Refreshes as soon as the response arrives (HX-Trigger) -->
+            <ul id="ex7-list" class="list-group mb-3"
+                hx-get="%s/exercise7/items"
+                hx-trigger="itemSaved from:body"
+                hx-swap="outerHTML">
+                <li class="list-group-item text-muted">No items yet</li>
+            </ul>
+
+            <div id="ex7-message"></div>
+            <pre id="ex7-events" class="small bg-light border rounded p-2"></pre>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise7/reset"
+                    hx-target="#ex7-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+
+    <script>
+        // Every event carries the JSON payload from the header in event.detail
+        ['itemSaved', 'itemCountChanged', 'showMessage'].forEach(name => {
+            document.body.addEventListener(name, (evt) => {
+                const { elt, ...detail } = evt.detail;
+                document.getElementById('ex7-events').textContent += name + ' ' + JSON.stringify(detail) + '\n';
+            });
+        });
+
+        // showMessage arrives last (HX-Trigger-After-Settle)
+        document.body.addEventListener('showMessage', (evt) => {
+            const box = document.getElementById('ex7-message');
+            box.className = 'alert alert-' + evt.detail.level + ' py-1 small';
+            box.textContent = evt.detail.text;
+        });
+    </script>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise7/go", serveSource("exercise7.go"))
+}
+
+func addExercise8CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise8", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 8: Sortable List</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+    <script src="https://cdn.jsdelivr.net/npm/sortablejs@1.15.2/Sortable.min.js"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 8: Sortable List</h1>
+        <p>Drag an item to reorder the list. The new order is saved on the server.</p>
+
+        <div id="ex8-demo">
+            <!-- Sortable fires "end" on the list; it bubbles up and triggers the form -->
+            <form id="ex8-list"
+                  hx-post="%s/exercise8/order"
+                  hx-trigger="end"
+                  hx-swap="outerHTML">
+                <!-- Lets the server detect an order based on an outdated list -->
+                <input type="hidden" name="version" value="1">
+                <div class="list-group sortable">
+                    <div class="list-group-item"><input type="hidden" name="item" value="1">☰ Learn Go basics</div>
+                    <div class="list-group-item"><input type="hidden" name="item" value="2">☰ Write an HTTP handler</div>
+                    <div class="list-group-item"><input type="hidden" name="item" value="3">☰ Add HTMX to a page</div>
+                    <div class="list-group-item"><input type="hidden" name="item" value="4">☰ Swap fragments from the server</div>
+                    <div class="list-group-item"><input type="hidden" name="item" value="5">☰ Ship it 🚀</div>
+                </div>
+            </form>
+
+            <div class="mt-3 d-flex align-items-center gap-2">
+                <button class="btn btn-sm btn-outline-warning"
+                        hx-post="%s/exercise8/shuffle"
+                        hx-target="#ex8-note">
+                    Simulate another tab
+                </button>
+                <div id="ex8-note"></div>
+            </div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise8/reset"
+                    hx-target="#ex8-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+
+    <script>
+        // Runs for the initial page and for every swapped-in fragment
+        htmx.onLoad((content) => {
+            content.querySelectorAll('.sortable').forEach(list => {
+                new Sortable(list, {
+                    animation: 150,
+                    // No more dragging until the server answers
+                    onEnd() { this.option('disabled', true); }
+                });
+            });
+        });
+
+        // HTMX does not swap 4xx responses by default.
+        // A 409 carries the current order, so show it.
+        document.body.addEventListener('htmx:beforeSwap', (evt) => {
+            const status = evt.detail.xhr.status;
+            if (evt.detail.target.id === 'ex8-list' && (status === 400 || status === 409)) {
+                evt.detail.shouldSwap = true;
+                evt.detail.isError = false;
+            }
+        });
+    </script>
+</body>
+</html>`, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise8/go", serveSource("exercise8.go"))
+}
+
+func addExercise9CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise9", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 9: Lazy Loading</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+    <style>
+        /* New content gets .htmx-added until it settles: fade it in */
+        .ex9-fade { transition: opacity 600ms ease-out; }
+        .ex9-fade.htmx-added { opacity: 0; }
+        .ex9-scroll { max-height: 260px; overflow-y: auto; }
+        .ex9-spacer { height: 200px; }
+    </style>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 9: Lazy Loading</h1>
+        <p>Each card loads its own content. The bottom cards wait until you scroll them into view.</p>
+
+        <div id="ex9-demo">
+            <div class="ex9-scroll">
+                <!-- Loads as soon as the card is on the page -->
+                <div class="card mb-2 ex9-card"
+                     hx-get="%s/exercise9/widget?card=weather"
+                     hx-trigger="load"
+                     hx-swap="outerHTML">
+                    <div class="card-body small text-muted">Loading Weather...</div>
+                </div>
+                <div class="card mb-2 ex9-card"
+                     hx-get="%s/exercise9/widget?card=stocks"
+                     hx-trigger="load"
+                     hx-swap="outerHTML">
+                    <div class="card-body small text-muted">Loading Stocks...</div>
+                </div>
+
+                <p class="small text-muted my-4">Scroll down ↓</p>
+                <div class="ex9-spacer"></div>
+
+                <!-- Loads the first time the card scrolls into view -->
+                <div class="card mb-2 ex9-card"
+                     hx-get="%s/exercise9/widget?card=news"
+                     hx-trigger="intersect once"
+                     hx-swap="outerHTML">
+                    <div class="card-body small text-muted">Loading News...</div>
+                </div>
+                <!-- The server fails this one on the first try -->
+                <div class="card mb-2 ex9-card"
+                     hx-get="%s/exercise9/widget?card=recommendations"
+                     hx-trigger="intersect once"
+                     hx-swap="outerHTML">
+                    <div class="card-body small text-muted">Loading Recommendations...</div>
+                </div>
+            </div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise9/reset"
+                    hx-target="#ex9-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+
+    <script>
+        // HTMX ignores 4xx/5xx responses by default.
+        // The server sends a fallback card with a Retry button, so swap it in.
+        document.body.addEventListener('htmx:beforeSwap', (evt) => {
+            if (evt.detail.xhr.status >= 400 && evt.detail.target.classList.contains('ex9-card')) {
+                evt.detail.shouldSwap = true;
+                evt.detail.isError = false;
+            }
+        });
+    </script>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise9/go", serveSource("exercise9.go"))
+}
+
+func addExercise10CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise10", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 10: Inline Validation</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 10: Inline Validation</h1>
+        <p>Each field is checked by the server as you type. Try jane.doe@example.com, it is already taken.</p>
+
+        <div id="ex10-demo">
+            <!-- The final submit checks every field again -->
+            <form id="ex10-form"
+                  hx-post="%s/exercise10/signup"
+                  hx-swap="outerHTML">
+                <div class="mb-2">
+                    <label for="ex10-email" class="form-label">Email</label>
+                    <!-- Validate 500ms after typing stops; replace the .error right after the input -->
+                    <input type="email" id="ex10-email" name="email" class="form-control"
+                           hx-post="%s/exercise10/validate/email"
+                           hx-trigger="keyup changed delay:500ms"
+                           hx-target="next .error"
+                           hx-swap="outerHTML">
+                    <div class="error small"></div>
+                </div>
+                <div class="mb-3">
+                    <label for="ex10-password" class="form-label">Password</label>
+                    <input type="password" id="ex10-password" name="password" class="form-control"
+                           hx-post="%s/exercise10/validate/password"
+                           hx-trigger="keyup changed delay:500ms"
+                           hx-target="next .error"
+                           hx-swap="outerHTML">
+                    <div class="error small"></div>
+                </div>
+                <button type="submit" class="btn btn-success">Sign Up</button>
+            </form>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise10/reset"
+                    hx-target="#ex10-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise10/go", serveSource("exercise10.go"))
+}
+
+func addExercise11CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise11", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 11: Trigger Filters & Modifiers</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 11: Trigger Filters & Modifiers</h1>
+        <p>The server counts every request. Compare its count with how often you clicked or pressed a key.</p>
+
+        <div id="ex11-demo">
+            <!-- Filter: only the Enter key sends a request -->
+            <input type="text" class="form-control mb-2" placeholder="Type and press Enter"
+                   hx-get="%s/exercise11/hit?variant=enter"
+                   hx-trigger="keyup[key=='Enter']"
+                   hx-target="#ex11-enter-out">
+            <div id="ex11-enter-out" class="mb-3">0 requests</div>
+
+            <!-- from:body listens on the whole page, not just this element -->
+            <span hx-get="%s/exercise11/hit?variant=hotkey"
+                  hx-trigger="keyup[key=='Escape'] from:body"
+                  hx-target="#ex11-hotkey-out">Press Escape anywhere on the page</span>
+            <div id="ex11-hotkey-out" class="mb-3">0 requests</div>
+
+            <!-- once: only the first click counts -->
+            <button class="btn btn-outline-primary"
+                    hx-get="%s/exercise11/hit?variant=once"
+                    hx-trigger="click once"
+                    hx-target="#ex11-once-out">Click me many times</button>
+            <div id="ex11-once-out" class="mb-3">0 requests</div>
+
+            <!-- throttle: at most one request every 2 seconds -->
+            <button class="btn btn-outline-primary"
+                    hx-get="%s/exercise11/hit?variant=throttle"
+                    hx-trigger="click throttle:2s"
+                    hx-target="#ex11-throttle-out">Click me fast</button>
+            <div id="ex11-throttle-out" class="mb-3">0 requests</div>
+
+            <!-- While a request is in flight, keep only the last click... -->
+            <button class="btn btn-outline-primary"
+                    hx-get="%s/exercise11/hit?variant=queue-last"
+                    hx-trigger="click queue:last"
+                    hx-target="#ex11-queue-last-out">Click me fast (slow server)</button>
+            <div id="ex11-queue-last-out" class="mb-3">0 requests</div>
+
+            <!-- ...or send every single one, one after another -->
+            <button class="btn btn-outline-primary"
+                    hx-get="%s/exercise11/hit?variant=queue-all"
+                    hx-trigger="click queue:all"
+                    hx-target="#ex11-queue-all-out">Click me fast (slow server)</button>
+            <div id="ex11-queue-all-out" class="mb-3">0 requests</div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise11/reset"
+                    hx-target="#ex11-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise11/go", serveSource("exercise11.go"))
+}
+
+func addExercise12CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise12", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 12: Request Synchronization</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 12: Request Synchronization</h1>
+        <p>The server answers each search after a random delay. Type quickly in both boxes and watch the request numbers.</p>
+
+        <div id="ex12-demo">
+            <!-- Every keystroke sends a request; whichever answers last wins -->
+            <label class="form-label">Without hx-sync</label>
+            <input type="search" name="q" class="form-control mb-2"
+                   hx-get="%s/exercise12/search?box=plain"
+                   hx-trigger="keyup changed"
+                   hx-target="#ex12-plain-out">
+            <div id="ex12-plain-out" class="mb-3"></div>
+
+            <!-- A new request aborts the one still in flight -->
+            <label class="form-label">With hx-sync="this:replace"</label>
+            <div class="input-group mb-2">
+                <input type="search" id="ex12-sync" name="q" class="form-control"
+                       hx-get="%s/exercise12/search?box=sync"
+                       hx-trigger="keyup changed"
+                       hx-sync="this:replace"
+                       hx-target="#ex12-sync-out">
+                <!-- htmx:abort cancels the element's in-flight request -->
+                <button type="button" class="btn btn-outline-danger"
+                        onclick="htmx.trigger('#ex12-sync', 'htmx:abort')">Abort</button>
+            </div>
+            <div id="ex12-sync-out"></div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise12/reset"
+                    hx-target="#ex12-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise12/go", serveSource("exercise12.go"))
+}
+
+func addExercise13CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise13", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 13: Error Handling</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+    <script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/response-targets.js"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 13: Error Handling</h1>
+        <p>Each button asks the server for a different failure. The status code decides where the response goes.</p>
+
+        <!-- Everything inside inherits these targets and the 2s timeout -->
+        <div id="ex13-demo"
+             hx-ext="response-targets"
+             hx-target="#ex13-result"
+             hx-target-4*="#ex13-errors"
+             hx-target-5*="#ex13-errors"
+             hx-request='{"timeout": 2000}'>
+            <button class="btn btn-outline-success" hx-get="%s/exercise13/error?code=200">200 OK</button>
+            <button class="btn btn-outline-danger" hx-get="%s/exercise13/error?code=400">400</button>
+            <button class="btn btn-outline-danger" hx-get="%s/exercise13/error?code=404">404</button>
+            <!-- A more specific target wins over the 4* wildcard -->
+            <button class="btn btn-outline-danger"
+                    hx-get="%s/exercise13/error?code=422"
+                    hx-target-422="#ex13-validation">422</button>
+            <button class="btn btn-outline-danger" hx-get="%s/exercise13/error?code=500">500</button>
+            <button class="btn btn-outline-danger" hx-get="%s/exercise13/error?code=timeout">Timeout</button>
+
+            <h6 class="mt-3">Success (hx-target)</h6>
+            <div id="ex13-result"></div>
+            <h6 class="mt-3">Validation (hx-target-422)</h6>
+            <div id="ex13-validation"></div>
+            <h6 class="mt-3">Errors (hx-target-4*, hx-target-5*)</h6>
+            <div id="ex13-errors"></div>
+            <pre id="ex13-log" class="small bg-light border rounded p-2 mt-3"></pre>
+        </div>
+    </div>
+
+    <script>
+        // Log every failed request, including the ones that never got a response
+        ['htmx:responseError', 'htmx:timeout', 'htmx:sendError'].forEach(name => {
+            document.body.addEventListener(name, (evt) => {
+                const status = evt.detail.xhr ? evt.detail.xhr.status : '';
+                document.getElementById('ex13-log').textContent += name + ' ' + status + ' ' + evt.detail.requestConfig.path + '\n';
+            });
+        });
+
+        // A timeout has no response to swap, so show a message ourselves
+        document.body.addEventListener('htmx:timeout', () => {
+            document.getElementById('ex13-errors').innerHTML =
+                '<div class="alert alert-secondary py-2 small mb-0">Request timed out after 2s.</div>';
+        });
+    </script>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise13/go", serveSource("exercise13.go"))
+}
+
+func addExercise14CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise14", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 14: Optimistic UI</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 14: Optimistic UI</h1>
+        <p>The heart updates instantly, then the server's count takes over. Open a second tab to watch the counts converge.</p>
+
+        <div id="ex14-demo">
+            <div class="list-group mb-3">
+                <!-- Polls so every tab catches up with everyone else's likes -->
+                <div id="ex14-post-1" class="list-group-item d-flex justify-content-between align-items-center"
+                     hx-get="%s/exercise14/like?post=1"
+                     hx-trigger="load, every 3s"
+                     hx-swap="outerHTML">
+                    <span>Why HTMX pairs well with Go</span>
+                    <!-- Update the page first (hx-on), then let the server's answer replace it -->
+                    <button class="btn btn-sm btn-outline-danger"
+                            hx-post="%s/exercise14/like?post=1"
+                            hx-vals='{"action": "like"}'
+                            hx-target="closest .list-group-item"
+                            hx-swap="outerHTML"
+                            hx-sync="closest .list-group-item:replace"
+                            hx-on:htmx:before-request="ex14Optimistic(this)">
+                        ♥ <span class="ex14-count">0</span>
+                    </button>
+                </div>
+            </div>
+            <button class="btn btn-sm btn-outline-secondary"
+                    hx-post="%s/exercise14/crowd"
+                    hx-target="#ex14-note">Simulate other visitors</button>
+            <span id="ex14-note"></span>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise14/reset"
+                    hx-target="#ex14-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+
+    <script>
+        // Guess the result before the server answers
+        function ex14Optimistic(btn) {
+            const count = btn.querySelector('.ex14-count');
+            const liked = !btn.classList.contains('btn-danger');
+            btn.classList.toggle('btn-danger', liked);
+            btn.classList.toggle('btn-outline-danger', !liked);
+            count.textContent = Number(count.textContent) + (liked ? 1 : -1);
+        }
+    </script>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise14/go", serveSource("exercise14.go"))
+}
+
+func addExercise15CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise15", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 15: Kanban Board</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+    <script src="https://cdn.jsdelivr.net/npm/sortablejs@1.15.2/Sortable.min.js"></script>
+    <style>
+        .ex15-column { min-height: 2.5rem; }
+    </style>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 15: Kanban Board</h1>
+        <p>Drag cards between columns, add cards with the inline forms and delete them with ×. The counts come back out of band.</p>
+
+        <div id="ex15-demo">
+            <div class="row g-2">
+                <div class="col">
+                    <div class="bg-light border rounded p-2">
+                        <!-- Replaced by hx-swap-oob="true" spans in every response -->
+                        <strong>To Do</strong> <span id="ex15-count-todo" class="badge bg-secondary">1</span>
+                        <div id="ex15-col-todo" class="ex15-column" data-column="todo">
+                            <!-- Sortable triggers "moved" on the card; hx-vals reads the event detail -->
+                            <div class="card card-body p-2 mb-2 ex15-card"
+                                 hx-post="%s/exercise15/move?id=1"
+                                 hx-trigger="moved"
+                                 hx-vals='js:{column: event.detail.column, index: event.detail.index}'
+                                 hx-swap="outerHTML">
+                                <div class="d-flex justify-content-between">
+                                    <span>Write the handlers</span>
+                                    <!-- An empty response + outerHTML removes the card -->
+                                    <button class="btn-close" aria-label="Delete"
+                                            hx-delete="%s/exercise15/cards?id=1"
+                                            hx-target="closest .ex15-card"
+                                            hx-swap="outerHTML"></button>
+                                </div>
+                            </div>
+                        </div>
+                        <!-- New cards are appended to the column; the form clears itself afterwards -->
+                        <form hx-post="%s/exercise15/cards"
+                              hx-target="#ex15-col-todo"
+                              hx-swap="beforeend"
+                              hx-on:htmx:after-request="if (event.detail.successful) this.reset()">
+                            <input type="hidden" name="column" value="todo">
+                            <input type="text" name="title" class="form-control form-control-sm" placeholder="+ Add card" required>
+                        </form>
+                    </div>
+                </div>
+                <!-- "Doing" and "Done" columns look the same -->
+            </div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise15/reset"
+                    hx-target="#ex15-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+
+    <script>
+        // Every column shares the "ex15" group, so cards can move between them
+        htmx.onLoad((content) => {
+            content.querySelectorAll('.ex15-column').forEach(column => {
+                new Sortable(column, {
+                    group: 'ex15',
+                    animation: 150,
+                    onEnd(evt) {
+                        if (evt.from === evt.to && evt.oldIndex === evt.newIndex) return;
+                        htmx.trigger(evt.item, 'moved', { column: evt.to.dataset.column, index: evt.newIndex });
+                    }
+                });
+            });
+        });
+    </script>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise15/go", serveSource("exercise15.go"))
+}
+
+func addExercise16CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise16", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 16: What Gets Sent</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 16: What Gets Sent</h1>
+        <p>Every button hits the same echo endpoint, which lists the parameters and HX-* headers it received.</p>
+
+        <!-- Not part of the form: only sent when something hx-includes it -->
+        <label for="ex16-outside" class="form-label">Outside the form</label>
+        <input type="text" id="ex16-outside" name="outside" class="form-control mb-3" value="included on request">
+
+        <form id="ex16-form" class="border rounded p-3 mb-3"
+              hx-post="%s/exercise16/echo"
+              hx-target="#ex16-echo">
+            <input type="text" name="name" class="form-control mb-2" value="Jane">
+            <input type="password" name="secret" class="form-control mb-2" value="hunter2">
+
+            <button type="submit" id="ex16-submit" class="btn btn-primary">Submit form</button>
+            <!-- Form values plus the outside input -->
+            <button type="button" id="ex16-include" class="btn btn-outline-primary"
+                    hx-post="%s/exercise16/echo"
+                    hx-include="#ex16-outside">+ hx-include</button>
+            <!-- Everything except the secret -->
+            <button type="button" id="ex16-filter" class="btn btn-outline-primary"
+                    hx-post="%s/exercise16/echo"
+                    hx-params="not secret">hx-params="not secret"</button>
+            <!-- Nothing at all -->
+            <button type="button" id="ex16-none" class="btn btn-outline-primary"
+                    hx-post="%s/exercise16/echo"
+                    hx-params="none">hx-params="none"</button>
+        </form>
+
+        <!-- Extra values: fixed JSON, or computed in the browser with js: -->
+        <button id="ex16-static" class="btn btn-outline-secondary"
+                hx-get="%s/exercise16/echo"
+                hx-vals='{"source": "static", "page": 2}'
+                hx-target="#ex16-echo">hx-vals (JSON)</button>
+        <button id="ex16-dynamic" class="btn btn-outline-secondary"
+                hx-get="%s/exercise16/echo"
+                hx-vals='js:{width: window.innerWidth, sentAt: new Date().toISOString()}'
+                hx-target="#ex16-echo">hx-vals (js:)</button>
+
+        <div id="ex16-echo" class="mt-3"></div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise16/reset"
+                    hx-target="#ex16-echo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise16/go", serveSource("exercise16.go"))
+}
+
+func addExercise17CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise17", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 17: Picking Fragments</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 17: Picking Fragments</h1>
+
+        <div id="ex17-demo">
+            <!-- The server answers with a complete page (full=1).
+                 hx-select keeps only the <article>, hx-select-oob also
+                 swaps the page's #ex17-stats into ours. Both are inherited. -->
+            <div hx-target="#ex17-select-out"
+                 hx-select="article"
+                 hx-select-oob="#ex17-stats">
+                <button class="btn btn-outline-primary" hx-get="%s/exercise17/article?page=1&full=1">Page 1</button>
+                <button class="btn btn-outline-primary" hx-get="%s/exercise17/article?page=2&full=1">Page 2</button>
+                <div id="ex17-select-out" class="mt-3"></div>
+            </div>
+            <div id="ex17-stats" class="text-muted">Stats arrive with hx-select-oob</div>
+
+            <!-- No full=1: the server sees HX-Request and renders only
+                 the "article" {{block}}, so there is nothing to select -->
+            <div hx-target="#ex17-block-out" class="mt-4">
+                <button class="btn btn-outline-success" hx-get="%s/exercise17/article?page=1">Page 1</button>
+                <button class="btn btn-outline-success" hx-get="%s/exercise17/article?page=2">Page 2</button>
+                <div id="ex17-block-out" class="mt-3"></div>
+            </div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise17/reset"
+                    hx-target="#ex17-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise17/go", serveSource("exercise17.go"))
+}
+
+func addExercise18CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise18", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 18: Multi-Step Wizard</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 18: Multi-Step Wizard</h1>
+
+        <div id="ex18-demo">
+            <!-- After Back/Forward htmx restores a snapshot of the markup,
+                 so the wrapper asks the server for its step again to get
+                 the saved values back -->
+            <div id="ex18-wizard"
+                 hx-get="%s/exercise18/step?n=1"
+                 hx-trigger="htmx:historyRestore from:body"
+                 hx-swap="outerHTML">
+
+                <!-- A valid step answers with the next one and an
+                     HX-Push-Url header; an invalid one comes back with errors -->
+                <form hx-post="%s/exercise18/step?n=1"
+                      hx-target="#ex18-wizard"
+                      hx-swap="outerHTML"
+                      novalidate>
+                    <input type="text" name="name" class="form-control mb-2" placeholder="Full name">
+                    <input type="email" name="email" class="form-control mb-2" placeholder="Email">
+                    <button type="submit" class="btn btn-success">Next →</button>
+                </form>
+
+                <!-- From step 2 on, Back loads the previous step and pushes its URL:
+                <button type="button" hx-get="/exercise18/step?n=1"
+                        hx-target="#ex18-wizard" hx-swap="outerHTML"
+                        hx-push-url="true">← Back</button> -->
+            </div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise18/reset"
+                    hx-target="#ex18-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise18/go", serveSource("exercise18.go"))
+}
+
+func addExercise19CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise19", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 19: Data Table</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 19: Data Table</h1>
+
+        <div id="ex19-demo">
+            <!-- The whole table is one form. Typing in a filter submits it
+                 (page 1, current sort from the hidden inputs); the server
+                 answers with the new table and an HX-Replace-Url header -->
+            <form id="ex19-table"
+                  hx-get="%s/exercise19/table"
+                  hx-trigger="input delay:300ms, submit"
+                  hx-target="this"
+                  hx-swap="outerHTML"
+                  hx-replace-url="true"
+                  hx-sync="this:replace">
+                <input type="hidden" name="sort" value="name">
+                <!-- Column toggles: each button carries the URL of the same table
+                     with that column hidden (hide=year) or shown again -->
+                <div id="ex19-columns">
+                    <button type="button" hx-get="%s/exercise19/table?hide=name">Name</button>
+                    <button type="button" hx-get="%s/exercise19/table?hide=year">Year</button>
+                </div>
+                <table class="table">
+                    <thead>
+                        <!-- Headers and page buttons carry complete URLs and inherit
+                             hx-target, hx-swap and hx-replace-url from the form -->
+                        <tr>
+                            <th><button type="button" hx-get="%s/exercise19/table?dir=desc">Name ▲</button></th>
+                            <th><button type="button" hx-get="%s/exercise19/table?sort=year">Year</button></th>
+                        </tr>
+                        <tr>
+                            <th><input type="search" id="ex19-name" name="name" placeholder="Filter"></th>
+                            <th></th>
+                        </tr>
+                    </thead>
+                    <tbody><!-- rows --></tbody>
+                </table>
+                <button type="button" hx-get="%s/exercise19/table?page=2">2</button>
+            </form>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise19/reset"
+                    hx-target="#ex19-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise19/go", serveSource("exercise19.go"))
+}
+
+func addExercise20CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise20", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 20: Preloading</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+    <script src="https://unpkg.com/htmx.org@1.9.12/dist/ext/preload.js"></script>
+    <script>
+        // Mark requests made by the extension (they have no triggering event)
+        // so the server can tell preloads from clicks
+        document.addEventListener('htmx:configRequest', (event) => {
+            if (event.detail.elt.hasAttribute('preload') && !event.detail.triggeringEvent) {
+                event.detail.headers['X-Preload'] = 'true';
+            }
+        });
+    </script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 20: Preloading</h1>
+
+        <div id="ex20-demo">
+            <div hx-ext="preload" hx-target="#ex20-view">
+                <!-- Plain: the request starts on click -->
+                <button hx-get="%s/exercise20/item?id=0&mode=none">Getting started</button>
+                <!-- Starts on mousedown, about 100ms before the click -->
+                <button hx-get="%s/exercise20/item?id=0&mode=mousedown" preload="mousedown">Getting started</button>
+                <!-- Starts after hovering for 100ms -->
+                <button hx-get="%s/exercise20/item?id=0&mode=mouseover" preload="mouseover">Getting started</button>
+            </div>
+
+            <!-- Each fragment posts back when it is shown, which updates these counts -->
+            <div id="ex20-view" class="mt-3"></div>
+            <div id="ex20-stats"></div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise20/reset"
+                    hx-target="#ex20-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise20/go", serveSource("exercise20.go"))
+}
+
+func addExercise21CodeEndpoints(mux *http.ServeMux, baseURL string) {
+	mux.HandleFunc("GET /code/exercise21", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Exercise 21: Duplicate Submissions</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <script src="https://unpkg.com/htmx.org@1.9.12"></script>
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Exercise 21: Duplicate Submissions</h1>
+
+        <div id="ex21-demo">
+            <!-- Unprotected: a second click is queued and sent
+                 as soon as the first request finishes -->
+            <form hx-post="%s/exercise21/order" hx-target="#ex21-result">
+                <input type="hidden" name="idempotency_key" value="3f9a1c0d5e7b2a64">
+                <select name="item" class="form-select"><option>Coffee</option></select>
+                <input type="number" name="quantity" value="1" class="form-control">
+                <button type="submit" class="btn btn-success">Place order</button>
+            </form>
+
+            <!-- Protected: the button is disabled while the request runs,
+                 and any request started meanwhile is dropped -->
+            <form hx-post="%s/exercise21/order" hx-target="#ex21-result"
+                  hx-disabled-elt="find button"
+                  hx-sync="this:drop">
+                <!-- The same key for every attempt at this order; the server
+                     processes it once and answers repeats with that result -->
+                <input type="hidden" name="idempotency_key" value="b81e4f0a92c3d756">
+                <select name="item" class="form-select"><option>Coffee</option></select>
+                <input type="number" name="quantity" value="1" class="form-control">
+                <button type="submit" class="btn btn-success">Place order</button>
+            </form>
+
+            <div id="ex21-result" class="mt-3"></div>
+            <!-- Updated out of band by every order response -->
+            <div id="ex21-counts"></div>
+        </div>
+
+        <div class="mt-3">
+            <button class="btn btn-secondary"
+                    hx-get="%s/exercise21/reset"
+                    hx-target="#ex21-demo">
+                Reset
+            </button>
+        </div>
+    </div>
+</body>
+</html>`, baseURL, baseURL, baseURL)
+	})
+
+	mux.HandleFunc("GET /code/exercise21/go", serveSource("exercise21.go"))
+}
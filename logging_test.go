@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := newLogger(&out, "json", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(accessLog(logger, newMux(func(path string) string { return path }, fakeClock{testTime}, noLatency)))
+	t.Cleanup(srv.Close)
+
+	form := url.Values{"name": {"Ann Gopher"}, "email": {"ann@example.com"}}
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/exercise6/contact/1?draft=secret", strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	req.Header.Set("HX-Target", "contact-1")
+	req.Header.Set("HX-Current-URL", "http://localhost/?q=jane#exercise4")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	var record struct {
+		Level    string
+		Msg      string
+		Method   string
+		Path     string
+		Status   int
+		Duration int64
+		Bytes    int
+		HTMX     map[string]string
+	}
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("%v in %s", err, out.Bytes())
+	}
+	if record.Level != "INFO" || record.Msg != "request" || record.Method != http.MethodPut ||
+		record.Path != "/exercise6/contact/1" || record.Status != http.StatusOK || record.Bytes == 0 {
+		t.Errorf("record = %+v", record)
+	}
+	wantHTMX := map[string]string{"request": "true", "target": "contact-1", "current_url": "http://localhost/"}
+	for k, v := range wantHTMX {
+		if record.HTMX[k] != v {
+			t.Errorf("htmx.%s = %q, want %q", k, record.HTMX[k], v)
+		}
+	}
+	if len(record.HTMX) != len(wantHTMX) {
+		t.Errorf("htmx = %v, want only the headers that were sent", record.HTMX)
+	}
+	for _, private := range []string{"Ann Gopher", "ann@example.com", "secret", "jane", "#exercise4"} {
+		if strings.Contains(out.String(), private) {
+			t.Errorf("log contains %q: %s", private, out.String())
+		}
+	}
+}
+
+func TestAccessLogLevels(t *testing.T) {
+	tests := []struct {
+		level  string
+		path   string
+		status int
+		logged bool
+	}{
+		{"", "/", http.StatusOK, true},
+		{"warn", "/", http.StatusOK, false},
+		{"warn", "/", http.StatusNotFound, true},
+		{"error", "/", http.StatusNotFound, false},
+		{"error", "/", http.StatusInternalServerError, true},
+		{"", "/healthz", http.StatusOK, false},
+		{"debug", "/healthz", http.StatusOK, true},
+		{"", "/readyz", http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		logger, err := newLogger(&out, "text", tt.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := accessLog(logger, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if logged := out.Len() > 0; logged != tt.logged {
+			t.Errorf("LOG_LEVEL=%q, %s, status %d: logged = %v, want %v", tt.level, tt.path, tt.status, logged, tt.logged)
+		}
+	}
+}
+
+func TestNewLoggerRejectsUnknownSettings(t *testing.T) {
+	if _, err := newLogger(&bytes.Buffer{}, "xml", ""); err == nil {
+		t.Error("LOG_FORMAT=xml: no error")
+	}
+	if _, err := newLogger(&bytes.Buffer{}, "json", "loud"); err == nil {
+		t.Error("LOG_LEVEL=loud: no error")
+	}
+}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"html/template"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os" // <-- Import the "os" package
+	"os/signal"
+	"syscall"
 	"time"
 )
 
@@ -20,7 +25,7 @@ func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, HX-Request, HX-Trigger, HX-Target, HX-Current-URL, HX-Boosted, HX-Trigger-Name, HX-Prompt")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, HX-Request, HX-Trigger, HX-Target, HX-Current-URL, HX-Boosted, HX-Trigger-Name, HX-Prompt, X-Preload")
 		w.Header().Set("Access-Control-Expose-Headers", "HX-Location, HX-Push-Url, HX-Redirect, HX-Refresh, HX-Replace-Url, HX-Reswap, HX-Retarget, HX-Reselect, HX-Trigger, HX-Trigger-After-Settle, HX-Trigger-After-Swap")
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -43,59 +48,113 @@ func main() {
 		return path
 	}
 
+	// --- Logging ---
+	// LOG_FORMAT=json switches the logs to JSON, LOG_LEVEL=warn hides successful requests.
+	logger, err := newLogger(os.Stderr, os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		log.Fatalf("Could not configure logging: %s\n", err)
+	}
+	slog.SetDefault(logger)
+
+	clock := realClock{}
+	mux := newMux(endpoint, clock, fullLatency)
+
+	// --- Metrics ---
+	// Exercise usage for Prometheus; the scrape itself is counted under route "other"
+	metrics := newMetrics()
+	mux.Handle("GET /metrics", metrics.handler())
+
+	// ----------------------------------------------------------------------------------
+	// SERVER STARTUP
+	// ----------------------------------------------------------------------------------
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080" // Default for local development
+	}
+
+	// --- Health ---
+	// /healthz for liveness, /readyz for readiness; readiness fails once a
+	// SIGTERM or SIGINT starts the shutdown
+	ready := &readiness{}
+	addHealthEndpoints(mux, ready)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	srv := newServer(":"+port, accessLog(logger, metrics.instrument(recordRequests(clock, mux, withNotFound(mux)))))
+	log.Printf("Server starting on port %s...", port)
+	if err := serve(ctx, srv, ready, drainDelay, shutdownTimeout); err != nil {
+		log.Fatalf("Could not start server: %s\n", err)
+	}
+	log.Println("Server stopped")
+}
+
+// newMux registers the main page, every exercise and the code listings on a new mux.
+// endpoint turns a path into the URL that the returned fragments point at, clock
+// stamps the fragments that show a time and latency sets how long slow ones wait.
+func newMux(endpoint func(string) string, clock Clock, latency Latency) *http.ServeMux {
+	mux := http.NewServeMux()
+
 	// ----------------------------------------------------------------------------------
 	// HANDLER FOR THE MAIN PAGE
 	// ----------------------------------------------------------------------------------
-	http.HandleFunc("/", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	// "/{$}" matches only the root; other unknown paths get the 404 page (see withNotFound)
+	mux.HandleFunc("GET /{$}", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		tmpl := template.Must(template.ParseFiles("templates/index.html"))
 		tmpl.Execute(w, nil)
 	}))
+	// Cross-origin preflights for every route, since the routes below only match their own methods
+	mux.HandleFunc("OPTIONS /", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {}))
 
 	// ----------------------------------------------------------------------------------
 	// HANDLERS FOR HTMX EXERCISES
 	// ----------------------------------------------------------------------------------
 
 	// Exercise 1: Click to Change Text
-	http.HandleFunc("/exercise1", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("POST /exercise1", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		fmt.Fprintf(w, `<button id="ex1-target" class="btn btn-success" hx-post="%s" hx-swap="outerHTML">Clicked! ✅</button>`, endpoint("/exercise1"))
 	}))
-	http.HandleFunc("/exercise1/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /exercise1/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		fmt.Fprintf(w, `<button id="ex1-target" class="btn btn-primary" hx-post="%s" hx-swap="outerHTML">Click Me</button>`, endpoint("/exercise1"))
 	}))
 
 	// Exercise 2: Simple Click to Load
-	http.HandleFunc("/exercise2", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /exercise2", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "Hello, HTMX! This content was loaded from the server. 🎉")
 	}))
-	http.HandleFunc("/exercise2/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /exercise2/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "")
 	}))
 
 	// Exercise 3: Polling for Updates
-	http.HandleFunc("/exercise3", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "Server time is: <strong>%s</strong>", time.Now().Format("03:04:05 PM"))
+	mux.HandleFunc("GET /exercise3", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "Server time is: <strong>%s</strong>", clock.Now().Format("03:04:05 PM"))
 	}))
-	http.HandleFunc("/exercise3/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /exercise3/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "Loading server time...")
 	}))
 
 	// Exercise 4: Echo User Input
-	http.HandleFunc("/exercise4", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /exercise4", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		userInput := r.URL.Query().Get("user-input")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
 		fmt.Fprintf(w, "You typed: <strong>%s</strong>", userInput)
 	}))
-	http.HandleFunc("/exercise4/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /exercise4/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, "")
 	}))
 
 	// Exercise 5: Form Submission
-	http.HandleFunc("/exercise5/submit", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		time.Sleep(1 * time.Second)
+	mux.HandleFunc("POST /exercise5/submit", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(latency(1 * time.Second))
 		name := r.PostFormValue("name")
-		log.Println("Received form submission:", name)
+		log.Println("Received form submission") // the name stays out of the logs
 		fmt.Fprintf(w, `<div class="alert alert-success" id="ex5-response">Thank you, %s! Your message has been received.</div>`, name)
 	}))
-	http.HandleFunc("/exercise5/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /exercise5/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
 		// Pass the dynamic URL into the template
 		tmpl := template.Must(template.New("form-reset").Parse(`
             <div id="ex5-response">
@@ -116,62 +175,74 @@ func main() {
 	}))
 
 	// Exercise 6: Click to Edit
-	http.HandleFunc("/exercise6/contact/1", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		// Pass dynamic URLs into the contact templates
-		data := map[string]interface{}{
+	// Pass dynamic URLs into the contact templates. Only contact 1 exists.
+	contact := func(id string) map[string]interface{} {
+		return map[string]interface{}{
+			"ID":        id,
 			"Name":      "Jane Doe",
 			"Email":     "jane.doe@example.com",
-			"ActionURL": endpoint("/exercise6/contact/1"),
+			"ActionURL": endpoint("/exercise6/contact/" + id),
 			"ResetURL":  endpoint("/exercise6/reset"),
 		}
-
-		if r.Method == http.MethodPut {
-			data["Name"] = r.PostFormValue("name")
-			data["Email"] = r.PostFormValue("email")
-			tmpl, _ := template.New("contact-view").Parse(contactViewTmpl)
-			tmpl.Execute(w, data)
+	}
+	// {id} is a path parameter; r.PathValue("id") reads it
+	mux.HandleFunc("GET /exercise6/contact/{id}", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			notFound(w, r)
 			return
 		}
-
 		tmpl, _ := template.New("contact-edit").Parse(contactEditTmpl)
-		tmpl.Execute(w, data)
+		tmpl.Execute(w, contact(r.PathValue("id")))
 	}))
-	http.HandleFunc("/exercise6/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		data := map[string]interface{}{
-			"Name":      "Jane Doe",
-			"Email":     "jane.doe@example.com",
-			"ActionURL": endpoint("/exercise6/contact/1"),
+	mux.HandleFunc("PUT /exercise6/contact/{id}", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			notFound(w, r)
+			return
 		}
+		data := contact(r.PathValue("id"))
+		data["Name"] = r.PostFormValue("name")
+		data["Email"] = r.PostFormValue("email")
 		tmpl, _ := template.New("contact-view").Parse(contactViewTmpl)
 		tmpl.Execute(w, data)
 	}))
+	mux.HandleFunc("GET /exercise6/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		tmpl, _ := template.New("contact-view").Parse(contactViewTmpl)
+		tmpl.Execute(w, contact("1"))
+	}))
 
-	addCodeEndpoints()
-
-	// ----------------------------------------------------------------------------------
-	// SERVER STARTUP
-	// ----------------------------------------------------------------------------------
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080" // Default for local development
-	}
-
-	log.Printf("Server starting on port %s...", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
-		log.Fatalf("Could not start server: %s\n", err)
-	}
+	// Exercises 7+ live in their own files
+	addExercise7Endpoints(mux, endpoint)
+	addExercise8Endpoints(mux, endpoint)
+	addExercise9Endpoints(mux, endpoint, latency)
+	addExercise10Endpoints(mux, endpoint)
+	addExercise11Endpoints(mux, endpoint, clock, latency)
+	addExercise12Endpoints(mux, endpoint, latency)
+	addExercise13Endpoints(mux, endpoint, latency)
+	addExercise14Endpoints(mux, endpoint, latency)
+	addExercise15Endpoints(mux, endpoint)
+	addExercise16Endpoints(mux, endpoint)
+	addExercise17Endpoints(mux, endpoint)
+	addExercise18Endpoints(mux, endpoint)
+	addExercise19Endpoints(mux, endpoint)
+	addExercise20Endpoints(mux, endpoint, clock, latency)
+	addExercise21Endpoints(mux, endpoint, clock, latency)
+	addInspectorEndpoints(mux)
+
+	addCodeEndpoints(mux)
+
+	return mux
 }
 
 // Templates for Exercise 6 now use template variables for URLs
 var contactViewTmpl = `
-<div id="contact-1" class="p-2 border rounded" hx-target="this" hx-swap="outerHTML">
+<div id="contact-{{.ID}}" class="p-2 border rounded" hx-target="this" hx-swap="outerHTML">
     <p class="mb-1"><strong>Name:</strong> {{.Name}}</p>
     <p class="mb-2"><strong>Email:</strong> {{.Email}}</p>
     <button class="btn btn-primary btn-sm" hx-get="{{.ActionURL}}">Click To Edit</button>
 </div>`
 
 var contactEditTmpl = `
-<div id="contact-1" hx-target="this" hx-swap="outerHTML">
+<div id="contact-{{.ID}}" hx-target="this" hx-swap="outerHTML">
     <form class="p-2 border rounded" hx-put="{{.ActionURL}}">
         <div class="mb-2">
             <label class="form-label small">Name</label>
@@ -182,16 +253,16 @@ var contactEditTmpl = `
             <input type="email" name="email" class="form-control form-control-sm" value="{{.Email}}">
         </div>
         <button type="submit" class="btn btn-success btn-sm">Save</button>
-        <button type="button" class="btn btn-secondary btn-sm" hx-get="{{.ResetURL}}" hx-target="#contact-1" hx-swap="outerHTML">Cancel</button>
+        <button type="button" class="btn btn-secondary btn-sm" hx-get="{{.ResetURL}}" hx-target="#contact-{{.ID}}" hx-swap="outerHTML">Cancel</button>
     </form>
 </div>`
 
 
 // Helper function to register all /code/* endpoints
-func addCodeEndpoints() {
+func addCodeEndpoints(mux *http.ServeMux) {
 	baseURL := "https://simple-htmx-go-tutorial-production.up.railway.app"
 
-	http.HandleFunc("/code/exercise1", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise1", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -225,7 +296,7 @@ func addCodeEndpoints() {
 </html>`, baseURL, baseURL)
 	})
 
-	http.HandleFunc("/code/exercise2", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise2", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -261,7 +332,7 @@ func addCodeEndpoints() {
 </html>`, baseURL, baseURL)
 	})
 
-	http.HandleFunc("/code/exercise3", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise3", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -294,7 +365,7 @@ func addCodeEndpoints() {
 </html>`, baseURL, baseURL)
 	})
 
-	http.HandleFunc("/code/exercise4", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise4", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -338,7 +409,7 @@ func addCodeEndpoints() {
 </html>`, baseURL, baseURL)
 	})
 
-	http.HandleFunc("/code/exercise5", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise5", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -394,7 +465,7 @@ func addCodeEndpoints() {
 </html>`, baseURL, baseURL)
 	})
 
-	http.HandleFunc("/code/exercise6", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise6", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `<!DOCTYPE html>
 <html lang="en">
 <head>
@@ -432,61 +503,61 @@ func addCodeEndpoints() {
 </html>`, baseURL, baseURL)
 	})
 
-	http.HandleFunc("/code/exercise1/go", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise1/go", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		fmt.Fprint(w, `// Exercise 1: Click to Change Text
-http.HandleFunc("/exercise1", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `// Exercise 1: Click to Change Text
+mux.HandleFunc("POST /exercise1", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     fmt.Fprintf(w, "<button id=\"ex1-target\" class=\"btn btn-success\" hx-post=\"%s\" hx-swap=\"outerHTML\">Clicked! ✅</button>", endpoint("/exercise1"))
 }))
-http.HandleFunc("/exercise1/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+mux.HandleFunc("GET /exercise1/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     fmt.Fprintf(w, "<button id=\"ex1-target\" class=\"btn btn-primary\" hx-post=\"%s\" hx-swap=\"outerHTML\">Click Me</button>", endpoint("/exercise1"))
 }))`)
 	})
 
-	http.HandleFunc("/code/exercise2/go", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise2/go", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		fmt.Fprint(w, `// Exercise 2: Simple Click to Load
-http.HandleFunc("/exercise2", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `// Exercise 2: Simple Click to Load
+mux.HandleFunc("GET /exercise2", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     fmt.Fprint(w, "Hello, HTMX! This content was loaded from the server. 🎉")
 }))
-http.HandleFunc("/exercise2/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+mux.HandleFunc("GET /exercise2/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     fmt.Fprint(w, "")
 }))`)
 	})
 
-	http.HandleFunc("/code/exercise3/go", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise3/go", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		fmt.Fprint(w, `// Exercise 3: Polling for Updates
-http.HandleFunc("/exercise3", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-    fmt.Fprintf(w, "Server time is: <strong>%s</strong>", time.Now().Format("03:04:05 PM"))
+		io.WriteString(w, `// Exercise 3: Polling for Updates
+mux.HandleFunc("GET /exercise3", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+    fmt.Fprintf(w, "Server time is: <strong>%s</strong>", clock.Now().Format("03:04:05 PM"))
 }))
-http.HandleFunc("/exercise3/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+mux.HandleFunc("GET /exercise3/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     fmt.Fprint(w, "Loading server time...")
 }))`)
 	})
 
-	http.HandleFunc("/code/exercise4/go", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise4/go", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		fmt.Fprint(w, `// Exercise 4: Echo User Input
-http.HandleFunc("/exercise4", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `// Exercise 4: Echo User Input
+mux.HandleFunc("GET /exercise4", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     userInput := r.URL.Query().Get("user-input")
     fmt.Fprintf(w, "You typed: <strong>%s</strong>", userInput)
 }))
-http.HandleFunc("/exercise4/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+mux.HandleFunc("GET /exercise4/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     fmt.Fprint(w, "")
 }))`)
 	})
 
-	http.HandleFunc("/code/exercise5/go", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise5/go", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		fmt.Fprint(w, `// Exercise 5: Form Submission
-http.HandleFunc("/exercise5/submit", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-    time.Sleep(1 * time.Second)
+		io.WriteString(w, `// Exercise 5: Form Submission
+mux.HandleFunc("POST /exercise5/submit", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+    time.Sleep(latency(1 * time.Second))
     name := r.PostFormValue("name")
-    log.Println("Received form submission:", name)
+    log.Println("Received form submission") // the name stays out of the logs
     fmt.Fprintf(w, "<div class=\"alert alert-success\" id=\"ex5-response\">Thank you, %s! Your message has been received.</div>", name)
 }))
-http.HandleFunc("/exercise5/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+mux.HandleFunc("GET /exercise5/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
     // Pass the dynamic URL into the template
     tmpl := template.Must(template.New("form-reset").Parse("\n        <div id=\"ex5-response\">\n            <form hx-post=\"{{.SubmitURL}}\" hx-target=\"#ex5-response\" hx-swap=\"outerHTML\" hx-indicator=\"#ex5-indicator\">\n                <div class=\"mb-3\">\n                    <label for=\"name\" class=\"form-label\">Name</label>\n                    <input type=\"text\" id=\"name\" name=\"name\" class=\"form-control\" required>\n                </div>\n                <button type=\"submit\" class=\"btn btn-success\">\n                    Submit <span class=\"spinner-border spinner-border-sm htmx-indicator\" id=\"ex5-indicator\"></span>\n                </button>\n            </form>\n        </div>\n    "))
     tmpl.Execute(w, map[string]string{
@@ -495,37 +566,58 @@ http.HandleFunc("/exercise5/reset", corsMiddleware(func(w http.ResponseWriter, r
 }))`)
 	})
 
-	http.HandleFunc("/code/exercise6/go", func(w http.ResponseWriter, r *http.Request) {
+	mux.HandleFunc("GET /code/exercise6/go", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
-		fmt.Fprint(w, `// Exercise 6: Click to Edit
-http.HandleFunc("/exercise6/contact/1", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-    // Pass dynamic URLs into the contact templates
-    data := map[string]interface{}{
+		io.WriteString(w, `// Exercise 6: Click to Edit
+// Pass dynamic URLs into the contact templates. Only contact 1 exists.
+contact := func(id string) map[string]interface{} {
+    return map[string]interface{}{
+        "ID":        id,
         "Name":      "Jane Doe",
         "Email":     "jane.doe@example.com",
-        "ActionURL": endpoint("/exercise6/contact/1"),
+        "ActionURL": endpoint("/exercise6/contact/" + id),
         "ResetURL":  endpoint("/exercise6/reset"),
     }
-
-    if r.Method == http.MethodPut {
-        data["Name"] = r.PostFormValue("name")
-        data["Email"] = r.PostFormValue("email")
-        tmpl, _ := template.New("contact-view").Parse(contactViewTmpl)
-        tmpl.Execute(w, data)
+}
+// {id} is a path parameter; r.PathValue("id") reads it
+mux.HandleFunc("GET /exercise6/contact/{id}", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+    if r.PathValue("id") != "1" {
+        notFound(w, r)
         return
     }
-
     tmpl, _ := template.New("contact-edit").Parse(contactEditTmpl)
-    tmpl.Execute(w, data)
+    tmpl.Execute(w, contact(r.PathValue("id")))
 }))
-http.HandleFunc("/exercise6/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-    data := map[string]interface{}{
-        "Name":      "Jane Doe",
-        "Email":     "jane.doe@example.com",
-        "ActionURL": endpoint("/exercise6/contact/1"),
+mux.HandleFunc("PUT /exercise6/contact/{id}", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+    if r.PathValue("id") != "1" {
+        notFound(w, r)
+        return
     }
+    data := contact(r.PathValue("id"))
+    data["Name"] = r.PostFormValue("name")
+    data["Email"] = r.PostFormValue("email")
     tmpl, _ := template.New("contact-view").Parse(contactViewTmpl)
     tmpl.Execute(w, data)
+}))
+mux.HandleFunc("GET /exercise6/reset", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+    tmpl, _ := template.New("contact-view").Parse(contactViewTmpl)
+    tmpl.Execute(w, contact("1"))
 }))`)
 	})
-}
\ No newline at end of file
+
+	addExercise7CodeEndpoints(mux, baseURL)
+	addExercise8CodeEndpoints(mux, baseURL)
+	addExercise9CodeEndpoints(mux, baseURL)
+	addExercise10CodeEndpoints(mux, baseURL)
+	addExercise11CodeEndpoints(mux, baseURL)
+	addExercise12CodeEndpoints(mux, baseURL)
+	addExercise13CodeEndpoints(mux, baseURL)
+	addExercise14CodeEndpoints(mux, baseURL)
+	addExercise15CodeEndpoints(mux, baseURL)
+	addExercise16CodeEndpoints(mux, baseURL)
+	addExercise17CodeEndpoints(mux, baseURL)
+	addExercise18CodeEndpoints(mux, baseURL)
+	addExercise19CodeEndpoints(mux, baseURL)
+	addExercise20CodeEndpoints(mux, baseURL)
+	addExercise21CodeEndpoints(mux, baseURL)
+}
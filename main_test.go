@@ -0,0 +1,380 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock always reads the same time, so timestamps in fragments are stable.
+type fakeClock struct{ t time.Time }
+
+func (c fakeClock) Now() time.Time { return c.t }
+
+var testTime = time.Date(2024, time.March, 1, 15, 4, 5, 0, time.UTC)
+
+// newTestServer serves the full mux with local (unprefixed) endpoints, a fake
+// clock and no simulated latency.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(withNotFound(newMux(func(path string) string { return path }, fakeClock{testTime}, noLatency)))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newVisitor is newTestServer with a cookie jar, so requests share one session.
+func newVisitor(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := newTestServer(t)
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Client().Jar = jar
+	return srv
+}
+
+// fetch sends an HTMX-style request, with form as the urlencoded body if given,
+// and returns the response along with its body.
+func fetch(t *testing.T, srv *httptest.Server, method, path string, form url.Values) (*http.Response, []byte) {
+	t.Helper()
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, srv.URL+path, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("HX-Request", "true")
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, got
+}
+
+func TestExercises1To6(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		form        url.Values
+		status      int
+		contentType string   // prefix; "" means no body is expected
+		contains    []string // markup the page relies on, e.g. reset targets
+	}{
+		{
+			name: "exercise1 click", method: http.MethodPost, path: "/exercise1",
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{`id="ex1-target"`, `hx-post="/exercise1"`, `hx-swap="outerHTML"`, "Clicked! ✅"},
+		},
+		{
+			name: "exercise1 reset", method: http.MethodGet, path: "/exercise1/reset",
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{`id="ex1-target"`, `hx-post="/exercise1"`, `hx-swap="outerHTML"`, "Click Me"},
+		},
+		{
+			name: "exercise2 load", method: http.MethodGet, path: "/exercise2",
+			status: http.StatusOK, contentType: "text/plain",
+			contains: []string{"Hello, HTMX!"},
+		},
+		{
+			name: "exercise2 reset", method: http.MethodGet, path: "/exercise2/reset",
+			status: http.StatusOK,
+		},
+		{
+			name: "exercise3 poll", method: http.MethodGet, path: "/exercise3",
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{"Server time is: <strong>03:04:05 PM</strong>"},
+		},
+		{
+			name: "exercise3 reset", method: http.MethodGet, path: "/exercise3/reset",
+			status: http.StatusOK, contentType: "text/plain",
+			contains: []string{"Loading server time..."},
+		},
+		{
+			name: "exercise4 echo", method: http.MethodGet, path: "/exercise4?user-input=gopher",
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{"You typed: <strong>gopher</strong>"},
+		},
+		{
+			name: "exercise4 reset", method: http.MethodGet, path: "/exercise4/reset",
+			status: http.StatusOK,
+		},
+		{
+			name: "exercise5 submit", method: http.MethodPost, path: "/exercise5/submit",
+			form:   url.Values{"name": {"Jane"}},
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{`id="ex5-response"`, "Thank you, Jane!"},
+		},
+		{
+			name: "exercise5 reset", method: http.MethodGet, path: "/exercise5/reset",
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{
+				`id="ex5-response"`, `hx-post="/exercise5/submit"`, `hx-target="#ex5-response"`,
+				`hx-swap="outerHTML"`, `hx-indicator="#ex5-indicator"`, `id="ex5-indicator"`, `name="name"`,
+			},
+		},
+		{
+			name: "exercise6 edit", method: http.MethodGet, path: "/exercise6/contact/1",
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{
+				`id="contact-1"`, `hx-target="this"`, `hx-put="/exercise6/contact/1"`,
+				`hx-get="/exercise6/reset"`, `hx-target="#contact-1"`, `value="Jane Doe"`, `value="jane.doe@example.com"`,
+			},
+		},
+		{
+			name: "exercise6 save", method: http.MethodPut, path: "/exercise6/contact/1",
+			form:   url.Values{"name": {"Ann Gopher"}, "email": {"ann@example.com"}},
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{`id="contact-1"`, `hx-target="this"`, `hx-get="/exercise6/contact/1"`, "Ann Gopher", "ann@example.com"},
+		},
+		{
+			name: "exercise6 reset", method: http.MethodGet, path: "/exercise6/reset",
+			status: http.StatusOK, contentType: "text/html",
+			contains: []string{`id="contact-1"`, `hx-target="this"`, `hx-get="/exercise6/contact/1"`, "Jane Doe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, got := fetch(t, srv, tt.method, tt.path, tt.form)
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.contentType == "" {
+				if len(got) != 0 {
+					t.Errorf("body = %q, want it empty", got)
+				}
+			} else if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %s", ct, tt.contentType)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(string(got), want) {
+					t.Errorf("response is missing %s\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRouting(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		htmx     bool
+		status   int
+		header   string // "Name: value" the response must carry
+		contains string
+	}{
+		{name: "index", method: http.MethodGet, path: "/", status: http.StatusOK, contains: "<!DOCTYPE html>"},
+		{name: "unknown page", method: http.MethodGet, path: "/exercise1/typo", status: http.StatusNotFound, contains: "Back to the tutorial"},
+		{name: "unknown fragment", method: http.MethodGet, path: "/exercise1/typo", htmx: true, status: http.StatusNotFound, contains: `<div class="alert alert-warning mb-0" role="alert">Nothing lives at <code>/exercise1/typo</code>.</div>`},
+		{name: "unknown path, any method", method: http.MethodPost, path: "/nope", htmx: true, status: http.StatusNotFound, contains: "Nothing lives at"},
+		{name: "wrong method", method: http.MethodGet, path: "/exercise1", status: http.StatusMethodNotAllowed, header: "Allow: OPTIONS, POST"},
+		{name: "wrong method on a shared path", method: http.MethodPatch, path: "/exercise15/cards", status: http.StatusMethodNotAllowed, header: "Allow: DELETE, OPTIONS, POST"},
+		{name: "unknown contact", method: http.MethodGet, path: "/exercise6/contact/2", htmx: true, status: http.StatusNotFound, contains: "/exercise6/contact/2"},
+		{name: "preflight", method: http.MethodOptions, path: "/exercise1", status: http.StatusOK, header: "Access-Control-Allow-Origin: *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.htmx {
+				req.Header.Set("HX-Request", "true")
+			}
+			resp, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if name, value, ok := strings.Cut(tt.header, ": "); ok && resp.Header.Get(name) != value {
+				t.Errorf("%s = %q, want %q", name, resp.Header.Get(name), value)
+			}
+			if !strings.Contains(string(got), tt.contains) {
+				t.Errorf("response is missing %s\n%s", tt.contains, got)
+			}
+			if tt.htmx && tt.status == http.StatusNotFound && strings.Contains(string(got), "<html") {
+				t.Error("htmx got the whole 404 page instead of a fragment")
+			}
+		})
+	}
+}
+
+func TestClockAndLatency(t *testing.T) {
+	srv := newTestServer(t)
+
+	// Exercise 20 waits 800ms by default and stamps the time it rendered
+	start := time.Now()
+	_, got := fetch(t, srv, http.MethodGet, "/exercise20/item?id=0&mode=none", nil)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %s, want no simulated latency", elapsed)
+	}
+	if want := "Rendered at 15:04:05.000"; !strings.Contains(string(got), want) {
+		t.Errorf("response is missing %s\n%s", want, got)
+	}
+}
+
+// A visitor keeps only the latest renders, however many items they load
+func TestExercise20KeepsTheLatestRenders(t *testing.T) {
+	srv := newVisitor(t)
+	for range 3 * ex20RenderWindow {
+		fetch(t, srv, http.MethodGet, "/exercise20/item?id=0&mode=none", nil)
+	}
+
+	_, got := fetch(t, srv, http.MethodPost, "/exercise20/seen?render=0", nil)
+	if want := "waited for the server: <strong>0</strong>"; !strings.Contains(string(got), want) {
+		t.Errorf("a render outside the window was counted\n%s", got)
+	}
+	_, got = fetch(t, srv, http.MethodPost, "/exercise20/seen?render="+strconv.Itoa(3*ex20RenderWindow-1), nil)
+	if want := "waited for the server: <strong>1</strong>"; !strings.Contains(string(got), want) {
+		t.Errorf("the latest render was not counted\n%s", got)
+	}
+}
+
+// Two visitors with their own cookie jars share the real likes but not the
+// simulated crowd's, and Reset takes back both of the visitor's own.
+func TestExercise14CrowdIsPerVisitor(t *testing.T) {
+	likes := func(srv *httptest.Server) int {
+		t.Helper()
+		_, got := fetch(t, srv, http.MethodGet, "/exercise14/like?post=1", nil)
+		m := regexp.MustCompile(`class="ex14-count">(\d+)<`).FindSubmatch(got)
+		if m == nil {
+			t.Fatalf("no like count in\n%s", got)
+		}
+		n, _ := strconv.Atoi(string(m[1]))
+		return n
+	}
+	a, b := newVisitor(t), newVisitor(t)
+	base := likes(b)
+
+	fetch(t, a, http.MethodPost, "/exercise14/crowd", nil)
+	fetch(t, a, http.MethodPost, "/exercise14/like?post=1", url.Values{"action": {"like"}})
+	if got := likes(a); got != base+6 {
+		t.Errorf("visitor a sees %d likes, want %d", got, base+6)
+	}
+	if got := likes(b); got != base+1 {
+		t.Errorf("visitor b sees %d likes, want %d (a's like but not a's crowd)", got, base+1)
+	}
+
+	fetch(t, a, http.MethodGet, "/exercise14/reset", nil)
+	if got := likes(a); got != base {
+		t.Errorf("after reset visitor a sees %d likes, want %d", got, base)
+	}
+	if got := likes(b); got != base {
+		t.Errorf("after a's reset visitor b sees %d likes, want %d", got, base)
+	}
+}
+
+func TestExercise18CannotSkipSteps(t *testing.T) {
+	srv := newVisitor(t)
+	step3 := url.Values{"terms": {"on"}}
+
+	// A fresh session has only reached step 1
+	_, got := fetch(t, srv, http.MethodPost, "/exercise18/step?n=3", step3)
+	if strings.Contains(string(got), "Order placed!") || !strings.Contains(string(got), `id="ex18-name"`) {
+		t.Fatalf("posting step 3 first should show step 1\n%s", got)
+	}
+
+	fetch(t, srv, http.MethodPost, "/exercise18/step?n=1", url.Values{"name": {"Ann Gopher"}, "email": {"ann@example.com"}})
+	fetch(t, srv, http.MethodPost, "/exercise18/step?n=2", url.Values{"street": {"1 Main St"}, "city": {"Gopherton"}, "postcode": {"12345"}})
+	// Going back and saving an invalid step 1 keeps step 3 reachable...
+	fetch(t, srv, http.MethodPost, "/exercise18/step?n=1", url.Values{"name": {""}, "email": {"ann@example.com"}})
+	// ...but the final submit checks it again and sends the visitor back there
+	resp, got := fetch(t, srv, http.MethodPost, "/exercise18/step?n=3", step3)
+	if strings.Contains(string(got), "Order placed!") || !strings.Contains(string(got), `id="ex18-name"`) {
+		t.Errorf("submitting with an invalid step 1 should show step 1\n%s", got)
+	}
+	if push := resp.Header.Get("HX-Push-Url"); push != "/exercise18/step?n=1" {
+		t.Errorf("HX-Push-Url = %q, want /exercise18/step?n=1", push)
+	}
+}
+
+func TestExercise19ColumnToggles(t *testing.T) {
+	q := parseTableQuery(url.Values{"hide": {"creator", "year", "bogus"}})
+	if want := []string{"year", "creator"}; !slices.Equal(q.Hide, want) {
+		t.Errorf("Hide = %q, want %q", q.Hide, want)
+	}
+	if got, want := q.Values().Encode(), "hide=year&hide=creator"; got != want {
+		t.Errorf("Values() = %q, want %q", got, want)
+	}
+	if q := parseTableQuery(url.Values{"hide": {"name", "year", "typing", "creator"}}); q.Hide != nil {
+		t.Errorf("hiding every column should show them all, got Hide = %q", q.Hide)
+	}
+
+	srv := newTestServer(t)
+	resp, got := fetch(t, srv, http.MethodGet, "/exercise19/table?hide=year&hide=creator&creator=Griesemer", nil)
+	if replace := resp.Header.Get("HX-Replace-Url"); replace != "/exercise19/table?creator=Griesemer&hide=year&hide=creator" {
+		t.Errorf("HX-Replace-Url = %q", replace)
+	}
+	for _, want := range []string{
+		`<tr><td>Go</td><td>static</td></tr>`,
+		// The form keeps the hidden columns and the filter that no longer has an input
+		`<input type="hidden" name="hide" value="year">`,
+		`<input type="hidden" name="creator" value="Griesemer">`,
+		// Showing Year again keeps everything else
+		`aria-pressed="false" hx-get="/exercise19/table?creator=Griesemer&amp;hide=creator">Year</button>`,
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("table is missing %s\n%s", want, got)
+		}
+	}
+	if strings.Contains(string(got), "2009") || strings.Contains(string(got), `id="ex19-creator"`) {
+		t.Errorf("hidden columns are still rendered\n%s", got)
+	}
+
+	// The last visible column can't be hidden
+	_, got = fetch(t, srv, http.MethodGet, "/exercise19/table?hide=name&hide=year&hide=typing", nil)
+	if !strings.Contains(string(got), `aria-pressed="true" disabled`) {
+		t.Errorf("the toggle of the last visible column should be disabled\n%s", got)
+	}
+}
+
+func TestExercise21ExpiresFinishedKeys(t *testing.T) {
+	s := newEx21State()
+	s.Entries["old"] = &ex21Entry{Finished: testTime.Add(-ex21KeyTTL - time.Second)}
+	s.Entries["recent"] = &ex21Entry{Finished: testTime.Add(-time.Minute)}
+	s.Entries["running"] = &ex21Entry{done: make(chan struct{})}
+
+	s.expire(testTime)
+	for key, want := range map[string]bool{"old": false, "recent": true, "running": true} {
+		if _, ok := s.Entries[key]; ok != want {
+			t.Errorf("key %q kept = %v, want %v", key, ok, want)
+		}
+	}
+}
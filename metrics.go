@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metrics counts which exercises get used, served at /metrics for Prometheus.
+// Requests are labelled by exercise and route:
+//
+//	demo   /exerciseN/...       the live demo's own requests
+//	reset  /exerciseN/reset     the Reset button, often a sign of being stuck
+//	code   /code/exerciseN...   the code listings
+//	page   /{$}                 the index page
+//	other  everything else: /metrics itself, preflights and unknown paths
+type metrics struct {
+	registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "htmx_tutorial",
+			Name:      "requests_total",
+			Help:      "Requests by exercise, route, status and whether htmx sent them (HX-Request).",
+		}, []string{"exercise", "route", "htmx", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "htmx_tutorial",
+			Name:      "request_duration_seconds",
+			Help:      "Time to answer a request, including the exercises' simulated latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"exercise", "route"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// handler serves the metrics in the Prometheus text format.
+func (m *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// instrument records every request that next serves.
+func (m *metrics) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		// The mux fills in the pattern it matched, which keeps typos and
+		// query strings out of the labels
+		exercise, route := classifyRoute(r.Pattern)
+		htmx := strconv.FormatBool(r.Header.Get("HX-Request") == "true")
+		m.requests.WithLabelValues(exercise, route, htmx, strconv.Itoa(rec.status)).Inc()
+		m.duration.WithLabelValues(exercise, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+var exercisePattern = regexp.MustCompile(`^/(code/)?exercise(\d+)(/.*)?$`)
+
+// classifyRoute maps a mux pattern such as "/exercise7/reset" to its exercise
+// number and route kind.
+func classifyRoute(pattern string) (exercise, route string) {
+	// Patterns may start with a method, e.g. "GET /exercise6/contact/{id}"
+	if i := strings.Index(pattern, " "); i >= 0 {
+		pattern = strings.TrimSpace(pattern[i+1:])
+	}
+	m := exercisePattern.FindStringSubmatch(pattern)
+	switch {
+	case pattern == "/{$}":
+		return "none", "page"
+	case m == nil:
+		return "none", "other"
+	case m[1] != "":
+		return m[2], "code"
+	case m[3] == "/reset":
+		return m[2], "reset"
+	}
+	return m[2], "demo"
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClassifyRoute(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		exercise string
+		route    string
+	}{
+		{"GET /{$}", "none", "page"},
+		{"OPTIONS /", "none", "other"},
+		{"/exercise1", "1", "demo"},
+		{"/exercise1/reset", "1", "reset"},
+		{"/exercise15/cards", "15", "demo"},
+		{"GET /exercise6/contact/{id}", "6", "demo"},
+		{"/code/exercise12", "12", "code"},
+		{"/code/exercise12/go", "12", "code"},
+		{"/metrics", "none", "other"},
+		{"", "none", "other"},
+	}
+	for _, tt := range tests {
+		exercise, route := classifyRoute(tt.pattern)
+		if exercise != tt.exercise || route != tt.route {
+			t.Errorf("classifyRoute(%q) = %q, %q, want %q, %q", tt.pattern, exercise, route, tt.exercise, tt.route)
+		}
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	m := newMetrics()
+	mux := newMux(func(path string) string { return path }, fakeClock{testTime}, noLatency)
+	mux.Handle("GET /metrics", m.handler())
+	srv := httptest.NewServer(m.instrument(mux))
+	t.Cleanup(srv.Close)
+
+	fetch(t, srv, http.MethodPost, "/exercise1", nil)
+	fetch(t, srv, http.MethodPost, "/exercise1", nil)
+	fetch(t, srv, http.MethodGet, "/exercise7/reset", nil)
+	resp, err := srv.Client().Get(srv.URL + "/code/exercise7") // a plain request, no HX-Request
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	_, body := fetch(t, srv, http.MethodGet, "/metrics", nil)
+	for _, want := range []string{
+		`htmx_tutorial_requests_total{exercise="1",htmx="true",route="demo",status="200"} 2`,
+		`htmx_tutorial_requests_total{exercise="7",htmx="true",route="reset",status="200"} 1`,
+		`htmx_tutorial_requests_total{exercise="7",htmx="false",route="code",status="200"} 1`,
+		`htmx_tutorial_request_duration_seconds_count{exercise="1",route="demo"} 2`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics are missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// ----------------------------------------------------------------------------------
+// Not Found
+// ----------------------------------------------------------------------------------
+// There is no catch-all route: a "/" pattern would also match the wrong methods of
+// every route and turn their 405s into 404s. withNotFound fills the gap instead.
+
+// withNotFound serves the 404 page for paths that no route knows under any method.
+// A path that exists under another method is left to the mux, which answers 405
+// with an Allow header.
+func withNotFound(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !routed(mux, r) {
+			notFound(w, r)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// routed reports whether some route matches the request's path, trying its own
+// method first.
+func routed(mux *http.ServeMux, r *http.Request) bool {
+	for _, method := range []string{r.Method, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		probe := *r
+		probe.Method = method
+		if _, pattern := mux.Handler(&probe); pattern != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// notFound answers 404 with a fragment when htmx asked, so it fits into a
+// hx-target-404 slot, and with a page otherwise.
+func notFound(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	name := "page"
+	if r.Header.Get("HX-Request") == "true" {
+		name = "fragment"
+	}
+	notFoundTmpl.ExecuteTemplate(w, name, r.URL.Path)
+}
+
+var notFoundTmpl = template.Must(template.New("notfound").Parse(`
+{{define "fragment"}}<div class="alert alert-warning mb-0" role="alert">Nothing lives at <code>{{.}}</code>.</div>{{end}}
+
+{{define "page"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Page Not Found</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.3.3/dist/css/bootstrap.min.css" rel="stylesheet">
+</head>
+<body>
+    <div class="container mt-5">
+        <h1>Page Not Found</h1>
+        <p>Nothing lives at <code>{{.}}</code>.</p>
+        <a class="btn btn-primary" href="/">Back to the tutorial</a>
+    </div>
+</body>
+</html>{{end}}
+`))
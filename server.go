@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ----------------------------------------------------------------------------------
+// Server Lifecycle
+// ----------------------------------------------------------------------------------
+// The slowest demo answers after 5s (exercise 13), so the write timeout leaves room
+// for it. None of the exercises stream (no SSE or WebSockets), so draining means
+// waiting for the in-flight requests; Shutdown does that for us.
+
+const (
+	readHeaderTimeout = 5 * time.Second
+	readTimeout       = 10 * time.Second
+	writeTimeout      = 30 * time.Second
+	idleTimeout       = 120 * time.Second
+
+	// How long /readyz fails before the listener closes, so the platform stops
+	// routing visitors here first
+	drainDelay = 5 * time.Second
+	// How long in-flight requests get to finish
+	shutdownTimeout = 20 * time.Second
+)
+
+func newServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}
+}
+
+// readiness is what /readyz reports; it turns false once shutdown starts.
+type readiness struct {
+	draining atomic.Bool
+}
+
+// addHealthEndpoints registers /healthz, which answers as long as the process
+// serves requests, and /readyz, which fails while the server drains.
+func addHealthEndpoints(mux *http.ServeMux, ready *readiness) {
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, "ok")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		if ready.draining.Load() {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "ready")
+	})
+}
+
+// serve runs srv until ctx is cancelled, then marks the server as not ready, waits
+// delay and shuts down, giving in-flight requests up to timeout to finish.
+func serve(ctx context.Context, srv *http.Server, ready *readiness, delay, timeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down", "drain_delay", delay, "timeout", timeout)
+	ready.draining.Store(true)
+	time.Sleep(delay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
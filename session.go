@@ -0,0 +1,122 @@
+package main
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Name of the cookie that identifies a visitor for the stateful exercises
+const sessionCookieName = "htmx_session"
+
+// sessionID returns the visitor's session ID, issuing a new cookie on first visit.
+// It must be called before anything is written to w.
+func sessionID(w http.ResponseWriter, r *http.Request) string {
+	if c, err := r.Cookie(sessionCookieName); err == nil && c.Value != "" {
+		return c.Value
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Without randomness every visitor would share a predictable ID
+		panic(fmt.Sprintf("session: reading random bytes: %v", err))
+	}
+	cookie := &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    hex.EncodeToString(b),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	http.SetCookie(w, cookie)
+	// Later lookups during the same request should see the new ID
+	r.AddCookie(cookie)
+	return cookie.Value
+}
+
+// Sessions nobody has used for sessionTTL are dropped, and a store never holds
+// more than maxSessions: every request without a cookie (curl, crawlers, probes)
+// starts a new one.
+const (
+	sessionTTL  = 1 * time.Hour
+	maxSessions = 10000
+)
+
+// sessionStore keeps one value of type T per visitor.
+// Each exercise that needs server-side state owns its own store.
+type sessionStore[T any] struct {
+	mu       sync.Mutex
+	sessions map[string]*session[T]
+	lru      *list.List // of *session[T], the most recently used first
+	init     func() *T
+	now      func() time.Time
+}
+
+type session[T any] struct {
+	id       string
+	state    *T
+	lastSeen time.Time
+	elem     *list.Element // in the store's lru list
+}
+
+func newSessionStore[T any](init func() *T) *sessionStore[T] {
+	return &sessionStore[T]{sessions: make(map[string]*session[T]), lru: list.New(), init: init, now: time.Now}
+}
+
+// with runs fn on the caller's state while holding the store lock,
+// creating the state on first use.
+func (s *sessionStore[T]) with(w http.ResponseWriter, r *http.Request, fn func(state *T)) {
+	id := sessionID(w, r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+	sess, ok := s.sessions[id]
+	if ok {
+		s.lru.MoveToFront(sess.elem)
+	} else {
+		if len(s.sessions) >= maxSessions {
+			s.remove(s.lru.Back().Value.(*session[T]))
+		}
+		sess = &session[T]{id: id, state: s.init()}
+		sess.elem = s.lru.PushFront(sess)
+		s.sessions[id] = sess
+	}
+	sess.lastSeen = now
+	fn(sess.state)
+}
+
+// sweep drops the sessions that have been idle for longer than sessionTTL. They
+// sit at the back of the lru list, so it stops at the first one still in use.
+// The caller holds s.mu.
+func (s *sessionStore[T]) sweep(now time.Time) {
+	for e := s.lru.Back(); e != nil; e = s.lru.Back() {
+		sess := e.Value.(*session[T])
+		if now.Sub(sess.lastSeen) <= sessionTTL {
+			return
+		}
+		s.remove(sess)
+	}
+}
+
+// remove drops a session. The caller holds s.mu.
+func (s *sessionStore[T]) remove(sess *session[T]) {
+	s.lru.Remove(sess.elem)
+	delete(s.sessions, sess.id)
+}
+
+// reset throws away the caller's state so the next call starts fresh.
+func (s *sessionStore[T]) reset(w http.ResponseWriter, r *http.Request) {
+	id := sessionID(w, r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sess, ok := s.sessions[id]; ok {
+		s.remove(sess)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// visit uses the store as the visitor with the given cookie; "" is a new visitor.
+func visit(s *sessionStore[int], id string) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if id != "" {
+		r.AddCookie(&http.Cookie{Name: sessionCookieName, Value: id})
+	}
+	s.with(httptest.NewRecorder(), r, func(n *int) { *n++ })
+}
+
+func TestSessionStoreDropsIdleSessions(t *testing.T) {
+	now := testTime
+	s := newSessionStore(func() *int { return new(int) })
+	s.now = func() time.Time { return now }
+
+	visit(s, "idle")
+	visit(s, "active")
+	for range 6 {
+		now = now.Add(sessionTTL / 5)
+		visit(s, "active")
+	}
+	if _, ok := s.sessions["idle"]; ok {
+		t.Error("a session idle for longer than sessionTTL was kept")
+	}
+	if got := s.sessions["active"]; got == nil || *got.state != 7 {
+		t.Error("the active session was lost")
+	}
+}
+
+func TestSessionStoreIsCapped(t *testing.T) {
+	now := testTime
+	s := newSessionStore(func() *int { return new(int) })
+	s.now = func() time.Time { now = now.Add(time.Millisecond); return now }
+
+	visit(s, "first")
+	for range maxSessions + 10 {
+		visit(s, "")
+	}
+	if len(s.sessions) > maxSessions {
+		t.Errorf("store holds %d sessions, want at most %d", len(s.sessions), maxSessions)
+	}
+	if _, ok := s.sessions["first"]; ok {
+		t.Error("the least recently used session was kept")
+	}
+}
+
+func TestSessionStoreKeepsRecentlyUsedSessions(t *testing.T) {
+	now := testTime
+	s := newSessionStore(func() *int { return new(int) })
+	s.now = func() time.Time { now = now.Add(time.Millisecond); return now }
+
+	visit(s, "first")
+	visit(s, "second")
+	for range maxSessions - 2 {
+		visit(s, "")
+	}
+	visit(s, "first")
+	visit(s, "")
+	if _, ok := s.sessions["first"]; !ok {
+		t.Error("a session used just now was evicted")
+	}
+	if _, ok := s.sessions["second"]; ok {
+		t.Error("the least recently used session was kept")
+	}
+	if len(s.sessions) != s.lru.Len() {
+		t.Errorf("%d sessions but %d in the lru list", len(s.sessions), s.lru.Len())
+	}
+}